@@ -3,13 +3,18 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
 
 	"github.com/charmbracelet/fang"
 	"github.com/everettraven/wranglr/pkg/cmd"
 )
 
 func main() {
-	if err := fang.Execute(context.TODO(), cmd.NewRootCommand()); err != nil {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := fang.Execute(ctx, cmd.NewRootCommand()); err != nil {
+		cancel()
 		os.Exit(1)
 	}
 }
@@ -1,24 +1,65 @@
 package cmd
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/everettraven/wranglr/pkg/printers"
 	"github.com/everettraven/wranglr/pkg/runner"
 	"github.com/spf13/cobra"
 )
 
 func NewRootCommand() *cobra.Command {
-	runOpts := &runner.Options{}
+	runOpts := &runner.Options{
+		Concurrency: runner.DefaultConcurrency,
+	}
 
 	cmd := &cobra.Command{
 		Use:   "wranglr",
 		Short: "wranglr is an engine for wrangling together work items based on a Starlark configuration",
 		Args:  cobra.ExactArgs(0),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			// validate the output format and its options before
+			// doing any work so misconfigurations fail fast.
+			_, err := printers.New(runOpts.OutputFormat, runOpts.OutputOptions)
+			if err != nil {
+				return err
+			}
+
+			if runOpts.RefreshInterval < 0 {
+				return fmt.Errorf("--refresh-interval must not be negative but was %s", runOpts.RefreshInterval)
+			}
+
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runOpts.Run(cmd.Context())
 		},
 	}
 
 	cmd.Flags().StringVarP(&runOpts.ConfigFile, "config", "c", runner.DefaultConfigPath(), "configures the Starlark file to be processed for configuration. Defaults to $HOME/.config/wranglr.star if possible to get your home directory. Otherwise it uses wranglr.star in the current directory.")
-	cmd.Flags().StringVarP(&runOpts.OutputFormat, "output", "o", "interactive", "configures the output format. Allowed values are [json, interactive]")
+	cmd.Flags().StringVarP(&runOpts.OutputFormat, "output", "o", "interactive", fmt.Sprintf("configures the output format. Allowed values are [%s]", strings.Join(printers.Names(), ", ")))
+	cmd.Flags().StringToStringVar(&runOpts.OutputOptions, "output-opt", nil, "configures output format specific options as key=value pairs. May be specified multiple times.")
+	cmd.Flags().DurationVar(&runOpts.CacheTTL, "cache-ttl", 0, "configures how long cached responses from sources are used without revalidating them. Cached responses are always revalidated using conditional requests when this is 0. Can be overridden per-search using the cache_ttl parameter.")
+	cmd.Flags().BoolVar(&runOpts.NoCache, "no-cache", false, "configures whether or not cached responses from sources should be ignored. Responses are still cached for future use.")
+	cmd.Flags().BoolVar(&runOpts.Offline, "offline", false, "configures whether or not sources should be queried. When offline, sources render the results of the last successful fetch for the same query and fail if there are none.")
+	cmd.Flags().BoolVar(&runOpts.ShowSnoozed, "show-snoozed", false, "configures whether or not items that were snoozed or dismissed in the interactive output are rendered.")
+	cmd.Flags().DurationVar(&runOpts.RefreshInterval, "refresh-interval", 0, "configures how often the interactive output refreshes its items by executing the configuration again. Items are only refreshed when pressing r when this is 0.")
+	cmd.Flags().StringSliceVar(&runOpts.LibPaths, "lib-path", nil, "configures directories searched for modules loaded with load(...) that aren't found relative to the file loading them. May be specified multiple times. ~/.config/wranglr/lib is always searched last.")
+	cmd.Flags().StringToStringVar(&runOpts.LibRepos, "lib-repo", nil, "configures local git checkouts that modules can be loaded from as name=dir pairs. Modules are loaded from them using load(\"@name//path.star\", ...), or load(\"@name@ref//path.star\", ...) to load them at a git ref. May be specified multiple times.")
+	cmd.Flags().IntVar(&runOpts.Concurrency, "concurrency", runOpts.Concurrency, "configures the maximum number of asynchronous fetches (i.e github.search_async(...)) that may run at the same time.")
+
+	cmd.Flags().BoolVar(&runOpts.Apply, "apply", false, "configures whether or not changes requested by the configuration (i.e github.add_labels(...)) are applied. When not applied, the requested changes are printed instead.")
+
+	cmd.MarkFlagsMutuallyExclusive("offline", "no-cache")
+	cmd.MarkFlagsMutuallyExclusive("offline", "apply")
+
+	err := cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return printers.Names(), cobra.ShellCompDirectiveNoFileComp
+	})
+	if err != nil {
+		panic(err)
+	}
 
 	return cmd
 }
@@ -0,0 +1,433 @@
+// Package httpcache implements an on-disk cache for HTTP responses
+// that is shared by all source modules.
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultDir returns the default directory cached responses are stored in.
+// This is $XDG_CACHE_HOME/wranglr, falling back to the platform-specific
+// user cache directory if $XDG_CACHE_HOME is not set.
+func DefaultDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "wranglr")
+	}
+
+	return filepath.Join(cacheDir, "wranglr")
+}
+
+type Options struct {
+	// Dir is the directory cached responses are stored in.
+	Dir string
+
+	// TTL is how long a cached response is used without
+	// revalidating it with the server. A TTL of zero means
+	// cached responses are always revalidated using conditional
+	// requests, which is still cheaper than a full request for
+	// servers that support them.
+	TTL time.Duration
+
+	// Disabled skips reading cached responses so that every request
+	// is sent to the server as-is. Successful responses are still stored.
+	// Cached responses are still used as a fallback when a request fails.
+	Disabled bool
+
+	// Offline serves every request from the cache, regardless of
+	// how old the cached response is, without contacting the server.
+	// Requests without a cached response, or that can't be
+	// cached at all (i.e writes), fail.
+	Offline bool
+
+	// Warn, if set, is called with a message whenever a stale cached
+	// response is served because the request to the server failed.
+	Warn func(msg string)
+}
+
+// StaleHeader is the header set on cached responses that are served
+// without being revalidated with the server because the server couldn't
+// be reached or the cache is in offline mode. The value is the time, in
+// RFC 3339 format, the response was last successfully fetched from the server.
+const StaleHeader = "X-Wranglr-Stale-Since"
+
+// StaleSince returns the time the response was last successfully fetched
+// from the server if the response is a stale cached response, or the zero
+// time otherwise.
+func StaleSince(resp *http.Response) time.Time {
+	staleSince, err := time.Parse(time.RFC3339, resp.Header.Get(StaleHeader))
+	if err != nil {
+		return time.Time{}
+	}
+	return staleSince
+}
+
+// Cache is an on-disk cache of HTTP responses.
+// A nil *Cache is valid and does not cache anything.
+type Cache struct {
+	dir      string
+	ttl      time.Duration
+	disabled bool
+	offline  bool
+	warn     func(msg string)
+	now      func() time.Time
+}
+
+func New(opts Options) *Cache {
+	dir := opts.Dir
+	if dir == "" {
+		dir = DefaultDir()
+	}
+
+	return &Cache{
+		dir:      dir,
+		ttl:      opts.TTL,
+		disabled: opts.Disabled,
+		offline:  opts.Offline,
+		warn:     opts.Warn,
+		now:      time.Now,
+	}
+}
+
+// Client returns an HTTP client that caches responses
+// to requests made using http.DefaultTransport.
+func (c *Cache) Client() *http.Client {
+	return &http.Client{
+		Transport: c.Transport(http.DefaultTransport),
+	}
+}
+
+// Transport returns an http.RoundTripper that caches
+// responses to requests made using base.
+func (c *Cache) Transport(base http.RoundTripper) http.RoundTripper {
+	if c == nil {
+		return base
+	}
+
+	return &transport{
+		cache: c,
+		base:  base,
+	}
+}
+
+// Staleness tracks the oldest stale cached response
+// observed across a series of responses.
+// It is safe for concurrent use.
+type Staleness struct {
+	mu    sync.Mutex
+	since time.Time
+}
+
+// Observe records the response if it is a stale cached response.
+func (s *Staleness) Observe(resp *http.Response) {
+	staleSince := StaleSince(resp)
+	if staleSince.IsZero() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.since.IsZero() || staleSince.Before(s.since) {
+		s.since = staleSince
+	}
+}
+
+// Since returns the time the oldest observed stale cached response was
+// last successfully fetched from the server, or the zero time if none
+// of the observed responses were stale.
+func (s *Staleness) Since() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.since
+}
+
+type ttlKey struct{}
+
+// WithTTL returns a context that overrides the TTL of the cache
+// for requests made with it.
+func WithTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, ttlKey{}, ttl)
+}
+
+func (c *Cache) ttlFor(req *http.Request) time.Duration {
+	if ttl, ok := req.Context().Value(ttlKey{}).(time.Duration); ok {
+		return ttl
+	}
+	return c.ttl
+}
+
+type warnKey struct{}
+
+// WithWarn returns a context that overrides the Warn function of the
+// cache for requests made with it, such as to show warnings somewhere
+// other than stderr while an interactive view is open.
+func WithWarn(ctx context.Context, warn func(msg string)) context.Context {
+	return context.WithValue(ctx, warnKey{}, warn)
+}
+
+func (c *Cache) warnFor(req *http.Request) func(msg string) {
+	if warn, ok := req.Context().Value(warnKey{}).(func(msg string)); ok {
+		return warn
+	}
+	return c.warn
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent returns a context that marks requests made with it as
+// idempotent, so that POST requests made with it (i.e GraphQL queries)
+// are cached like GET requests, keyed by their body.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// cacheable returns whether responses to the request may be cached.
+func cacheable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost:
+		idempotent, _ := req.Context().Value(idempotentKey{}).(bool)
+		return idempotent && (req.Body == nil || req.GetBody != nil)
+	default:
+		return false
+	}
+}
+
+type transport struct {
+	cache *Cache
+	base  http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !cacheable(req) {
+		if t.cache.offline {
+			return nil, fmt.Errorf("can't send %s request to %s while offline", req.Method, req.URL.Redacted())
+		}
+		return t.base.RoundTrip(req)
+	}
+
+	key, err := cacheKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// a missing or unreadable entry is treated as a cache miss
+	cached, _ := t.cache.load(key)
+
+	if t.cache.offline {
+		if cached == nil {
+			return nil, fmt.Errorf("no cached response available for %s while offline", req.URL.Redacted())
+		}
+		return cached.staleResponse(req), nil
+	}
+
+	if cached != nil && !t.cache.disabled && t.cache.now().Sub(cached.StoredAt) < t.cache.ttlFor(req) {
+		return cached.response(req), nil
+	}
+
+	outReq := req
+	if cached != nil && !t.cache.disabled {
+		outReq = req.Clone(req.Context())
+		if cached.ETag != "" {
+			outReq.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			outReq.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(outReq)
+	if err != nil {
+		if cached != nil && req.Context().Err() == nil {
+			t.cache.warnStale(req, cached, err.Error())
+			return cached.staleResponse(req), nil
+		}
+		return nil, err
+	}
+
+	if cached != nil && shouldFallback(resp) {
+		_ = resp.Body.Close()
+		t.cache.warnStale(req, cached, resp.Status)
+		return cached.staleResponse(req), nil
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		_ = resp.Body.Close()
+
+		// caching is best-effort, failing to update
+		// the entry shouldn't fail the request.
+		cached.StoredAt = t.cache.now()
+		_ = t.cache.store(key, cached)
+
+		return cached.response(req), nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// caching is best-effort, failing to store
+	// the entry shouldn't fail the request.
+	_ = t.cache.store(key, &entry{
+		URL:          req.URL.String(),
+		StoredAt:     t.cache.now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		Body:         body,
+	})
+
+	return resp, nil
+}
+
+// shouldFallback returns whether a response indicates the
+// server is unavailable, in which case a stale cached response
+// is preferable to failing.
+func shouldFallback(resp *http.Response) bool {
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+func (c *Cache) warnStale(req *http.Request, cached *entry, reason string) {
+	warn := c.warnFor(req)
+	if warn == nil {
+		return
+	}
+
+	warn(fmt.Sprintf("request to %s failed (%s), using cached response from %s", req.URL.Redacted(), reason, cached.StoredAt.Format(time.RFC3339)))
+}
+
+// cacheKey identifies a request in the cache. Every header of the
+// request is part of the key, as any of them may change the content
+// of the response or carry credentials (i.e custom authentication
+// headers), so that responses are never shared across different
+// credentials or representations. The body of the request, if any,
+// is also part of the key.
+func cacheKey(req *http.Request) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%s\n", req.Method, req.URL.String())
+	for _, name := range slices.Sorted(maps.Keys(req.Header)) {
+		_, _ = fmt.Fprintf(h, "%s: %q\n", name, req.Header[name])
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return "", fmt.Errorf("reading request body: %w", err)
+		}
+		defer func() { _ = body.Close() }()
+
+		if _, err := io.Copy(h, body); err != nil {
+			return "", fmt.Errorf("reading request body: %w", err)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+type entry struct {
+	URL          string      `json:"url"`
+	StoredAt     time.Time   `json:"storedAt"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	StatusCode   int         `json:"statusCode"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+func (e *entry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func (e *entry) staleResponse(req *http.Request) *http.Response {
+	resp := e.response(req)
+	resp.Header.Set(StaleHeader, e.StoredAt.Format(time.RFC3339))
+	return resp
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+func (c *Cache) load(key string) (*entry, error) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &entry{}
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+func (c *Cache) store(key string, e *entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it so that
+	// concurrent readers never observe a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
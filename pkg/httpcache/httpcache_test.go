@@ -0,0 +1,78 @@
+package httpcache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOfflineRefusesWrites(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := New(Options{Dir: t.TempDir(), Offline: true}).Client()
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(context.Background(), method, server.URL, strings.NewReader(`{}`))
+			if err != nil {
+				t.Fatalf("building request: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if err == nil {
+				_ = resp.Body.Close()
+				t.Fatalf("expected %s request to be refused while offline", method)
+			}
+			if !strings.Contains(err.Error(), "while offline") {
+				t.Errorf("got error %q, want it to mention being offline", err)
+			}
+		})
+	}
+
+	if n := received.Load(); n != 0 {
+		t.Errorf("expected no requests to reach the server, got %d", n)
+	}
+}
+
+func TestCacheKeyHeaders(t *testing.T) {
+	newRequest := func(header http.Header) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, "https://example.com/items", nil)
+		if err != nil {
+			t.Fatalf("building request: %v", err)
+		}
+		req.Header = header
+		return req
+	}
+
+	key := func(header http.Header) string {
+		k, err := cacheKey(newRequest(header))
+		if err != nil {
+			t.Fatalf("computing key: %v", err)
+		}
+		return k
+	}
+
+	base := key(http.Header{"X-Api-Key": {"one"}})
+	for name, header := range map[string]http.Header{
+		"different value":  {"X-Api-Key": {"two"}},
+		"different header": {"X-Other-Key": {"one"}},
+		"extra header":     {"X-Api-Key": {"one"}, "X-Tenant": {"a"}},
+		"no headers":       {},
+	} {
+		if key(header) == base {
+			t.Errorf("%s: got the same key as the base request", name)
+		}
+	}
+
+	if key(http.Header{"X-Api-Key": {"one"}}) != base {
+		t.Error("got different keys for the same request")
+	}
+}
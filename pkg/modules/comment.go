@@ -0,0 +1,95 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.starlark.net/starlark"
+)
+
+// Commentable is implemented by items whose
+// comments can be fetched from their source.
+type Commentable interface {
+	Item
+
+	// Comments fetches the comments on the item, oldest first.
+	Comments(ctx context.Context) (*CommentsResult, error)
+}
+
+// CommentsResult is the result of fetching the comments on an item.
+type CommentsResult struct {
+	Comments []*Comment
+
+	// StaleSince is the time the oldest of the comments was last successfully
+	// fetched if any of them were served from the cache because the source
+	// couldn't be reached or wranglr is running offline. It is the zero time
+	// if all comments are fresh.
+	StaleSince time.Time
+}
+
+// FetchCommentsAttr is the name of the item method
+// used to fetch the comments on an item from Starlark.
+const FetchCommentsAttr = "fetch_comments"
+
+// FetchCommentsBuiltin returns the builtin that fetches the comments on the
+// item. Comments aren't returned by searches, so they are only fetched
+// when the builtin is called.
+func FetchCommentsBuiltin(item Commentable) *starlark.Builtin {
+	return starlark.NewBuiltin(FetchCommentsAttr, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		if err := starlark.UnpackArgs(fn.Name(), args, kwargs); err != nil {
+			return nil, err
+		}
+
+		result, err := item.Comments(Context(thread))
+		if err != nil {
+			return nil, fmt.Errorf("%s: fetching comments for %s: %w", fn.Name(), item.ID(), err)
+		}
+
+		elems := []starlark.Value{}
+		for _, comment := range result.Comments {
+			elems = append(elems, comment)
+		}
+
+		return starlark.NewList(elems), nil
+	})
+}
+
+// Comment is a comment on an item.
+type Comment struct {
+	Author string
+
+	// Body is the content of the comment as Markdown.
+	Body string
+
+	CreatedAt time.Time
+}
+
+var _ starlark.HasAttrs = (*Comment)(nil)
+
+func (c *Comment) String() string        { return fmt.Sprintf("comment(author=%q)", c.Author) }
+func (c *Comment) Type() string          { return "comment" }
+func (c *Comment) Truth() starlark.Bool  { return starlark.True }
+func (c *Comment) Freeze()               {}
+func (c *Comment) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
+
+func (c *Comment) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "author":
+		return starlark.String(c.Author), nil
+	case "body":
+		return starlark.String(c.Body), nil
+	case "created_at":
+		return Time(c.CreatedAt), nil
+	default:
+		return nil, nil
+	}
+}
+
+func (c *Comment) AttrNames() []string {
+	return []string{
+		"author",
+		"body",
+		"created_at",
+	}
+}
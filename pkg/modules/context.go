@@ -0,0 +1,48 @@
+package modules
+
+import (
+	"context"
+
+	"go.starlark.net/starlark"
+)
+
+const (
+	contextLocal     = "wranglr.context"
+	concurrencyLocal = "wranglr.concurrency"
+)
+
+// SetContext sets the context that builtins executed
+// by the thread should use for any blocking operations.
+func SetContext(thread *starlark.Thread, ctx context.Context) {
+	thread.SetLocal(contextLocal, ctx)
+}
+
+// Context returns the context that builtins executed by
+// the thread should use for any blocking operations.
+// If no context has been set on the thread, context.Background()
+// is returned.
+func Context(thread *starlark.Thread) context.Context {
+	if ctx, ok := thread.Local(contextLocal).(context.Context); ok {
+		return ctx
+	}
+	return context.Background()
+}
+
+// SetConcurrency sets the maximum number of futures started
+// by the thread that may be running at the same time.
+func SetConcurrency(thread *starlark.Thread, limit int) {
+	if limit <= 0 {
+		limit = 1
+	}
+	thread.SetLocal(concurrencyLocal, make(chan struct{}, limit))
+}
+
+func semaphore(thread *starlark.Thread) chan struct{} {
+	if sem, ok := thread.Local(concurrencyLocal).(chan struct{}); ok {
+		return sem
+	}
+
+	sem := make(chan struct{}, 1)
+	thread.SetLocal(concurrencyLocal, sem)
+	return sem
+}
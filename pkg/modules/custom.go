@@ -0,0 +1,116 @@
+package modules
+
+import (
+	"fmt"
+	"maps"
+
+	"go.starlark.net/starlark"
+)
+
+// CustomFields returns the custom fields set on the item by the
+// configuration (i.e item.owner_team = "sig-auth"), by name.
+func (b *BaseItem) CustomFields() map[string]starlark.Value {
+	return maps.Clone(b.custom)
+}
+
+// SetItemField sets the field with the provided name on item, which embeds b.
+// The mutable wranglr-specific fields are set as usual, and any other name
+// that isn't already an attribute of the item is set as a custom field.
+// Source items implement SetField using it so that custom fields can't
+// shadow their attributes.
+func (b *BaseItem) SetItemField(item starlark.HasAttrs, name string, val starlark.Value) error {
+	switch name {
+	case StatusAttr, PriorityAttr, GroupAttr:
+		return b.SetField(name, val)
+	}
+
+	if _, ok := b.custom[name]; !ok {
+		if existing, err := item.Attr(name); existing != nil && err == nil {
+			return fmt.Errorf("cannot set field %q, which is a read-only attribute of %s", name, item.Type())
+		}
+	}
+
+	if err := checkCustom(val); err != nil {
+		return fmt.Errorf("custom field %q %w", name, err)
+	}
+
+	if b.custom == nil {
+		b.custom = map[string]starlark.Value{}
+	}
+	b.custom[name] = val
+	return nil
+}
+
+// checkCustom returns an error if the value can't be used as a custom field,
+// which must be representable as JSON so that it can be output.
+func checkCustom(val starlark.Value) error {
+	switch v := val.(type) {
+	case starlark.NoneType, starlark.Bool, starlark.Int, starlark.Float, starlark.String:
+		return nil
+	case *starlark.List:
+		for elem := range v.Elements() {
+			if err := checkCustom(elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case starlark.Tuple:
+		for _, elem := range v {
+			if err := checkCustom(elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *starlark.Dict:
+		for key, value := range v.Entries() {
+			if _, ok := key.(starlark.String); !ok {
+				return fmt.Errorf("must only contain dicts with string keys but contained a key of type %q", key.Type())
+			}
+			if err := checkCustom(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("must be None, a bool, number, string, list or dict but was type %q", val.Type())
+	}
+}
+
+// JSONValue converts the value of a custom field to the equivalent
+// value that can be encoded as JSON.
+func JSONValue(val starlark.Value) any {
+	switch v := val.(type) {
+	case starlark.Bool:
+		return bool(v)
+	case starlark.Int:
+		if i, ok := v.Int64(); ok {
+			return i
+		}
+		return v.String()
+	case starlark.Float:
+		return float64(v)
+	case starlark.String:
+		return string(v)
+	case *starlark.List:
+		elems := []any{}
+		for elem := range v.Elements() {
+			elems = append(elems, JSONValue(elem))
+		}
+		return elems
+	case starlark.Tuple:
+		elems := []any{}
+		for _, elem := range v {
+			elems = append(elems, JSONValue(elem))
+		}
+		return elems
+	case *starlark.Dict:
+		obj := map[string]any{}
+		for key, value := range v.Entries() {
+			str, _ := starlark.AsString(key)
+			obj[str] = JSONValue(value)
+		}
+		return obj
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,32 @@
+package modules
+
+import (
+	"time"
+
+	startime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+)
+
+// OptionalDuration is a starlark.Unpacker for optional duration
+// arguments that can tell whether the argument was provided.
+// Durations can be provided as time.duration values or as strings
+// accepted by time.parse_duration (i.e "10m").
+type OptionalDuration struct {
+	Duration time.Duration
+	Set      bool
+}
+
+func (od *OptionalDuration) Unpack(v starlark.Value) error {
+	if v == starlark.None {
+		return nil
+	}
+
+	var d startime.Duration
+	if err := d.Unpack(v); err != nil {
+		return err
+	}
+
+	od.Duration = time.Duration(d)
+	od.Set = true
+	return nil
+}
@@ -0,0 +1,117 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+
+	"go.starlark.net/starlark"
+)
+
+// FetchFunc fetches a Starlark value, typically from a remote source.
+// FetchFuncs may be executed outside of the Starlark thread and must not
+// use it, or any values that may be accessed by it, while they run.
+type FetchFunc func(ctx context.Context) (starlark.Value, error)
+
+// FetchBuilder unpacks the arguments of a builtin
+// into a FetchFunc that performs the actual work.
+type FetchBuilder func(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (FetchFunc, error)
+
+// SyncBuiltin returns a builtin that runs the FetchFunc returned by
+// build on the calling thread and returns its result.
+func SyncBuiltin(build FetchBuilder) BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		fetch, err := build(fn, args, kwargs)
+		if err != nil {
+			return nil, err
+		}
+
+		return withState(thread, fetch)(Context(thread))
+	}
+}
+
+// AsyncBuiltin returns a builtin that runs the FetchFunc returned by
+// build in the background and immediately returns a Future for its result.
+func AsyncBuiltin(build FetchBuilder) BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		fetch, err := build(fn, args, kwargs)
+		if err != nil {
+			return nil, err
+		}
+
+		return Go(thread, fn.Name(), withState(thread, fetch)), nil
+	}
+}
+
+// withState wraps fetch so that it sets whether the items it fetches
+// have been seen or snoozed, using the ItemState of the thread.
+func withState(thread *starlark.Thread, fetch FetchFunc) FetchFunc {
+	state := itemState(thread)
+	return func(ctx context.Context) (starlark.Value, error) {
+		value, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		applyState(state, value)
+		return value, nil
+	}
+}
+
+// Future is a Starlark value representing the
+// result of a FetchFunc running in the background.
+type Future struct {
+	name  string
+	done  chan struct{}
+	value starlark.Value
+	err   error
+}
+
+// Go runs fetch in the background, respecting the concurrency
+// limit of the thread, and returns a Future for its result.
+func Go(thread *starlark.Thread, name string, fetch FetchFunc) *Future {
+	f := &Future{
+		name: name,
+		done: make(chan struct{}),
+	}
+
+	ctx := Context(thread)
+	sem := semaphore(thread)
+
+	go func() {
+		defer close(f.done)
+
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		case <-ctx.Done():
+			f.err = ctx.Err()
+			return
+		}
+
+		f.value, f.err = fetch(ctx)
+	}()
+
+	return f
+}
+
+// Wait blocks until the future has completed, or the
+// context is done, and returns the result of the future.
+func (f *Future) Wait(ctx context.Context) (starlark.Value, error) {
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for %s: %w", f.name, ctx.Err())
+	}
+
+	if f.err != nil {
+		return nil, fmt.Errorf("%s: %w", f.name, f.err)
+	}
+
+	return f.value, nil
+}
+
+func (f *Future) String() string        { return fmt.Sprintf("<future %s>", f.name) }
+func (f *Future) Type() string          { return "future" }
+func (f *Future) Truth() starlark.Bool  { return starlark.True }
+func (f *Future) Freeze()               {}
+func (f *Future) Hash() (uint32, error) { return 0, fmt.Errorf("unhashable type: future") }
@@ -0,0 +1,117 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// CurrentUser returns the login of the authenticated user.
+func (c *Client) CurrentUser(ctx context.Context) (string, error) {
+	user := struct {
+		Login string `json:"login"`
+	}{}
+
+	_, err := c.get(ctx, c.endpoints.REST+"/user", &user, &httpcache.Staleness{})
+	if err != nil {
+		return "", fmt.Errorf("fetching authenticated user: %w", err)
+	}
+
+	return user.Login, nil
+}
+
+// AddComment comments on an issue or pull request.
+func (c *Client) AddComment(ctx context.Context, repository string, number int, body string) error {
+	return c.send(ctx, http.MethodPost, c.issuePath(repository, number, "/comments"), map[string]any{
+		"body": body,
+	})
+}
+
+// AddLabels adds labels to an issue or pull request.
+func (c *Client) AddLabels(ctx context.Context, repository string, number int, labels ...string) error {
+	return c.send(ctx, http.MethodPost, c.issuePath(repository, number, "/labels"), map[string]any{
+		"labels": labels,
+	})
+}
+
+// RemoveLabel removes a label from an issue or pull request.
+func (c *Client) RemoveLabel(ctx context.Context, repository string, number int, label string) error {
+	return c.send(ctx, http.MethodDelete, c.issuePath(repository, number, "/labels/"+url.PathEscape(label)), nil)
+}
+
+// AddAssignees adds assignees to an issue or pull request.
+func (c *Client) AddAssignees(ctx context.Context, repository string, number int, assignees ...string) error {
+	return c.send(ctx, http.MethodPost, c.issuePath(repository, number, "/assignees"), map[string]any{
+		"assignees": assignees,
+	})
+}
+
+// SetAssignees replaces the assignees of an issue or pull request.
+func (c *Client) SetAssignees(ctx context.Context, repository string, number int, assignees ...string) error {
+	return c.send(ctx, http.MethodPatch, c.issuePath(repository, number, ""), map[string]any{
+		"assignees": assignees,
+	})
+}
+
+// Close closes an issue or pull request.
+func (c *Client) Close(ctx context.Context, repository string, number int) error {
+	return c.send(ctx, http.MethodPatch, c.issuePath(repository, number, ""), map[string]any{
+		"state": "closed",
+	})
+}
+
+// Approve submits an approving review on a pull request.
+func (c *Client) Approve(ctx context.Context, repository string, number int) error {
+	path := fmt.Sprintf("%s/repos/%s/pulls/%d/reviews", c.endpoints.REST, repository, number)
+	return c.send(ctx, http.MethodPost, path, map[string]any{
+		"event": "APPROVE",
+	})
+}
+
+func (c *Client) issuePath(repository string, number int, suffix string) string {
+	return fmt.Sprintf("%s/repos/%s/issues/%d%s", c.endpoints.REST, repository, number, suffix)
+}
+
+// send sends a request to the REST API that modifies a
+// resource, with body encoded as the JSON request body.
+func (c *Client) send(ctx context.Context, method, uri string, body any) error {
+	var reqBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshalling request body: %w", err)
+		}
+		reqBody = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, uri, reqBody)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/vnd.github.v3+json")
+
+	if authToken := getAuthToken(c.host); authToken != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("token %s", authToken))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status %q: %s", resp.Status, bodyBytes)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// recordedRequest is a request received by an actionsServer.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// actionsServer is a GitHub API that responds to requests for the
+// authenticated user with the login "octocat", and records every other
+// request, responding to it with an empty JSON object.
+type actionsServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+func newActionsServer(t *testing.T) *actionsServer {
+	t.Helper()
+	setTestToken(t)
+
+	server := &actionsServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v3/user" {
+			_, _ = io.WriteString(w, `{"login": "octocat"}`)
+			return
+		}
+
+		recorded := recordedRequest{Method: r.Method, Path: r.URL.Path}
+		if body, _ := io.ReadAll(r.Body); len(body) > 0 {
+			if err := json.Unmarshal(body, &recorded.Body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		server.mu.Lock()
+		server.requests = append(server.requests, recorded)
+		server.mu.Unlock()
+
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (s *actionsServer) recorded() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]recordedRequest{}, s.requests...)
+}
+
+func TestActions(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		action func(ctx context.Context, c *Client) error
+		want   recordedRequest
+	}{
+		{
+			name: "add comment",
+			action: func(ctx context.Context, c *Client) error {
+				return c.AddComment(ctx, "octo/repo", 1, "LGTM")
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/api/v3/repos/octo/repo/issues/1/comments",
+				Body:   map[string]any{"body": "LGTM"},
+			},
+		},
+		{
+			name: "add labels",
+			action: func(ctx context.Context, c *Client) error {
+				return c.AddLabels(ctx, "octo/repo", 1, "bug", "triage")
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/api/v3/repos/octo/repo/issues/1/labels",
+				Body:   map[string]any{"labels": []any{"bug", "triage"}},
+			},
+		},
+		{
+			name: "remove label",
+			action: func(ctx context.Context, c *Client) error {
+				return c.RemoveLabel(ctx, "octo/repo", 1, "good first issue")
+			},
+			want: recordedRequest{
+				Method: http.MethodDelete,
+				Path:   "/api/v3/repos/octo/repo/issues/1/labels/good first issue",
+			},
+		},
+		{
+			name: "add assignees",
+			action: func(ctx context.Context, c *Client) error {
+				login, err := c.CurrentUser(ctx)
+				if err != nil {
+					return err
+				}
+				return c.AddAssignees(ctx, "octo/repo", 1, login)
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/api/v3/repos/octo/repo/issues/1/assignees",
+				Body:   map[string]any{"assignees": []any{"octocat"}},
+			},
+		},
+		{
+			name: "close",
+			action: func(ctx context.Context, c *Client) error {
+				return c.Close(ctx, "octo/repo", 1)
+			},
+			want: recordedRequest{
+				Method: http.MethodPatch,
+				Path:   "/api/v3/repos/octo/repo/issues/1",
+				Body:   map[string]any{"state": "closed"},
+			},
+		},
+		{
+			name: "approve",
+			action: func(ctx context.Context, c *Client) error {
+				return c.Approve(ctx, "octo/repo", 1)
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/api/v3/repos/octo/repo/pulls/1/reviews",
+				Body:   map[string]any{"event": "APPROVE"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newActionsServer(t)
+			cache := httpcache.New(httpcache.Options{Dir: t.TempDir()})
+			client := NewClient(server.URL, cache.Client())
+
+			if err := tc.action(context.Background(), client); err != nil {
+				t.Fatalf("running action: %v", err)
+			}
+
+			requests := server.recorded()
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1: %+v", len(requests), requests)
+			}
+			if !reflect.DeepEqual(requests[0], tc.want) {
+				t.Errorf("got request %+v, want %+v", requests[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestActionsOffline(t *testing.T) {
+	server := newActionsServer(t)
+	cache := httpcache.New(httpcache.Options{Dir: t.TempDir(), Offline: true})
+	client := NewClient(server.URL, cache.Client())
+
+	if err := client.AddComment(context.Background(), "octo/repo", 1, "LGTM"); err == nil {
+		t.Error("expected commenting to fail while offline")
+	}
+	if requests := server.recorded(); len(requests) != 0 {
+		t.Errorf("expected no requests to be sent while offline, got %+v", requests)
+	}
+}
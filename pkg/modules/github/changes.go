@@ -0,0 +1,120 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+const (
+	AddLabelsAttr    = "add_labels"
+	CommentAttr      = "comment"
+	SetAssigneesAttr = "set_assignees"
+)
+
+// AddLabelsBuiltin adds a change to the plan that adds labels to an
+// item. Labels the item already has are ignored, and no change is
+// added if the item already has all of the labels.
+func AddLabelsBuiltin() modules.BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var item *Item
+		var labelsList *starlark.List
+
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+			"item", &item,
+			"labels", &labelsList,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		labels, err := modules.AsStrings(labelsList)
+		if err != nil {
+			return nil, fmt.Errorf("%s: labels %w", fn.Name(), err)
+		}
+
+		existing := item.Labels()
+		missing := []string{}
+		for _, label := range labels {
+			if !slices.Contains(existing, label) && !slices.Contains(missing, label) {
+				missing = append(missing, label)
+			}
+		}
+
+		if len(missing) == 0 {
+			return starlark.None, nil
+		}
+
+		modules.PlanFor(thread).Add(modules.NewChange(item, fmt.Sprintf("add labels %q", missing), func(ctx context.Context) error {
+			return item.client.AddLabels(ctx, item.Repository(), item.issue.Number, missing...)
+		}))
+
+		return starlark.None, nil
+	}
+}
+
+// CommentBuiltin adds a change to the plan that comments on an item.
+func CommentBuiltin() modules.BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var item *Item
+		var body string
+
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+			"item", &item,
+			"body", &body,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if body == "" {
+			return nil, fmt.Errorf("%s: body must not be empty", fn.Name())
+		}
+
+		modules.PlanFor(thread).Add(modules.NewChange(item, fmt.Sprintf("comment %q", body), func(ctx context.Context) error {
+			return item.client.AddComment(ctx, item.Repository(), item.issue.Number, body)
+		}))
+
+		return starlark.None, nil
+	}
+}
+
+// SetAssigneesBuiltin adds a change to the plan that replaces the
+// assignees of an item. No change is added if the item already
+// has exactly the assignees.
+func SetAssigneesBuiltin() modules.BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var item *Item
+		var assigneesList *starlark.List
+
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+			"item", &item,
+			"assignees", &assigneesList,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		assignees, err := modules.AsStrings(assigneesList)
+		if err != nil {
+			return nil, fmt.Errorf("%s: assignees %w", fn.Name(), err)
+		}
+
+		existing := item.Assignees()
+		slices.Sort(existing)
+		sorted := slices.Sorted(slices.Values(assignees))
+		if slices.Equal(existing, slices.Compact(sorted)) {
+			return starlark.None, nil
+		}
+
+		modules.PlanFor(thread).Add(modules.NewChange(item, fmt.Sprintf("set assignees %q", assignees), func(ctx context.Context) error {
+			return item.client.SetAssignees(ctx, item.Repository(), item.issue.Number, assignees...)
+		}))
+
+		return starlark.None, nil
+	}
+}
@@ -0,0 +1,134 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/search"
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+func TestChanges(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		attr   string
+		kwargs []starlark.Tuple
+		want   recordedRequest
+	}{
+		{
+			name: "add labels",
+			attr: AddLabelsAttr,
+			kwargs: []starlark.Tuple{
+				{starlark.String("labels"), starlark.NewList([]starlark.Value{starlark.String("bug"), starlark.String("triage")})},
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/api/v3/repos/octo/repo/issues/1/labels",
+				Body:   map[string]any{"labels": []any{"triage"}},
+			},
+		},
+		{
+			name: "comment",
+			attr: CommentAttr,
+			kwargs: []starlark.Tuple{
+				{starlark.String("body"), starlark.String("Needs triage")},
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/api/v3/repos/octo/repo/issues/1/comments",
+				Body:   map[string]any{"body": "Needs triage"},
+			},
+		},
+		{
+			name: "set assignees",
+			attr: SetAssigneesAttr,
+			kwargs: []starlark.Tuple{
+				{starlark.String("assignees"), starlark.NewList([]starlark.Value{starlark.String("hubot")})},
+			},
+			want: recordedRequest{
+				Method: http.MethodPatch,
+				Path:   "/api/v3/repos/octo/repo/issues/1",
+				Body:   map[string]any{"assignees": []any{"hubot"}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newActionsServer(t)
+			cache := httpcache.New(httpcache.Options{Dir: t.TempDir()})
+
+			item := NewItem(search.Issue{
+				Number:        1,
+				RepositoryURL: server.URL + "/api/v3/repos/octo/repo",
+				Labels:        []search.Label{{Name: "bug"}},
+				Assignees:     []search.User{{Login: "octocat"}},
+			}, "", time.Time{})
+			item.client = NewClient(server.URL, cache.Client())
+
+			module := &Module{Cache: cache}
+			builtin, err := module.Attr(tc.attr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			thread := &starlark.Thread{Name: "test"}
+			plan := modules.PlanFor(thread)
+			if _, err := starlark.Call(thread, builtin, starlark.Tuple{item}, tc.kwargs); err != nil {
+				t.Fatalf("calling %s: %v", tc.attr, err)
+			}
+
+			// changes are only planned until they are applied
+			if requests := server.recorded(); len(requests) != 0 {
+				t.Fatalf("expected no requests before applying, got %+v", requests)
+			}
+
+			changes := plan.Changes()
+			if len(changes) != 1 {
+				t.Fatalf("got %d changes, want 1", len(changes))
+			}
+			if err := changes[0].Apply(context.Background()); err != nil {
+				t.Fatalf("applying %q: %v", changes[0].Description, err)
+			}
+
+			requests := server.recorded()
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1: %+v", len(requests), requests)
+			}
+			if !reflect.DeepEqual(requests[0], tc.want) {
+				t.Errorf("got request %+v, want %+v", requests[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestChangesAlreadyMade(t *testing.T) {
+	item := NewItem(search.Issue{
+		Number:    1,
+		Labels:    []search.Label{{Name: "bug"}},
+		Assignees: []search.User{{Login: "octocat"}},
+	}, "", time.Time{})
+
+	thread := &starlark.Thread{Name: "test"}
+	plan := modules.PlanFor(thread)
+	for _, call := range []struct {
+		fn     modules.BuiltinFunc
+		name   string
+		kwargs []starlark.Tuple
+	}{
+		{AddLabelsBuiltin(), AddLabelsAttr, []starlark.Tuple{{starlark.String("labels"), starlark.NewList([]starlark.Value{starlark.String("bug")})}}},
+		{SetAssigneesBuiltin(), SetAssigneesAttr, []starlark.Tuple{{starlark.String("assignees"), starlark.NewList([]starlark.Value{starlark.String("octocat")})}}},
+	} {
+		if _, err := starlark.Call(thread, starlark.NewBuiltin(call.name, call.fn), starlark.Tuple{item}, call.kwargs); err != nil {
+			t.Fatalf("calling %s: %v", call.name, err)
+		}
+	}
+
+	if changes := plan.Changes(); len(changes) != 0 {
+		t.Errorf("expected no changes for labels and assignees the item already has, got %d", len(changes))
+	}
+}
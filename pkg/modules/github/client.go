@@ -7,71 +7,273 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cli/cli/v2/pkg/search"
 	"github.com/cli/go-gh/v2/pkg/auth"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+const (
+	// SearchResultCeiling is the maximum number of results
+	// the GitHub search API will return for a single query.
+	SearchResultCeiling = 1000
+
+	// MaxPerPage is the maximum page size supported by the
+	// GitHub search API.
+	MaxPerPage = 100
 )
 
 type Client struct {
 	host       string
+	endpoints  Endpoints
 	httpClient *http.Client
 }
 
-func NewClient(host string) *Client {
-	return &Client{
-		host:       host,
-		httpClient: http.DefaultClient,
+type ClientOption func(c *Client)
+
+// WithEndpoints overrides the API endpoints derived from the host.
+func WithEndpoints(endpoints Endpoints) ClientOption {
+	return func(c *Client) {
+		c.endpoints = endpoints
+	}
+}
+
+// NewClient creates a client for the GitHub host. The host may optionally
+// include a scheme (i.e "https://github.example.com"). The API endpoints
+// are derived from the host, see EndpointsForHost.
+func NewClient(host string, httpClient *http.Client, opts ...ClientOption) *Client {
+	_, hostname := splitHost(host)
+
+	c := &Client{
+		host:       hostname,
+		endpoints:  EndpointsForHost(host),
+		httpClient: httpClient,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// SearchOptions configures how results are fetched
+// from the GitHub search API.
+type SearchOptions struct {
+	// Limit is the maximum number of results to return per query.
+	// Values less than or equal to zero, or greater than the
+	// search result ceiling, default to the search result ceiling.
+	Limit int
+
+	// PerPage is the number of results to request per page.
+	// Values less than or equal to zero, or greater than the
+	// maximum page size, default to the maximum page size.
+	// It is never more than the limit.
+	PerPage int
+
+	// Enrich configures which additional data is
+	// fetched for the pull requests in the results.
+	Enrich EnrichOptions
+}
+
+func (so SearchOptions) limit() int {
+	if so.Limit <= 0 || so.Limit > SearchResultCeiling {
+		return SearchResultCeiling
 	}
+	return so.Limit
+}
+
+// perPage returns the page size to request, which is never more
+// than the limit so that small limits don't fetch a full page.
+func (so SearchOptions) perPage() int {
+	perPage := so.PerPage
+	if perPage <= 0 || perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+	return min(perPage, so.limit())
+}
+
+// SearchResult is the aggregated result of one or more search queries.
+type SearchResult struct {
+	Issues []search.Issue
+
+	// TotalCount is the total number of results GitHub reported
+	// as matching the queries, which may be more than the number of
+	// issues returned.
+	TotalCount int
+
+	// IncompleteResults is true if GitHub reported that the search
+	// timed out before finding all results or if the results were
+	// truncated due to the configured limit or the search result ceiling.
+	IncompleteResults bool
+
+	// PullRequestDetails are the details of the pull requests in
+	// the results. It is only populated when enrichment is enabled.
+	PullRequestDetails map[PullRequestRef]*PullRequestDetails
+
+	// StaleSince is the time the oldest of the results was last successfully
+	// fetched from GitHub if any of the results were served from the cache
+	// because GitHub couldn't be reached or wranglr is running offline.
+	// It is the zero time if all results are fresh.
+	StaleSince time.Time
 }
 
-func (c *Client) Issues(ctx context.Context, queries ...string) ([]search.Issue, error) {
-	path := fmt.Sprintf("https://api.%s/search/issues", c.host)
+func (c *Client) Issues(ctx context.Context, opts SearchOptions, queries ...string) (*SearchResult, error) {
+	path := fmt.Sprintf("%s/search/issues", c.endpoints.REST)
+
+	out := &SearchResult{
+		Issues: []search.Issue{},
+	}
+	staleness := &httpcache.Staleness{}
 
-	out := []search.Issue{}
 	for _, query := range queries {
 		qs := url.Values{}
 		qs.Set("q", query)
+		qs.Set("per_page", strconv.Itoa(opts.perPage()))
 
 		uri := fmt.Sprintf("%s?%s", path, qs.Encode())
-		req, err := http.NewRequest(http.MethodGet, uri, nil)
-		if err != nil {
-			return nil, fmt.Errorf("building request: %w", err)
-		}
+		issues := []search.Issue{}
+		total := 0
 
-		req.Header.Add("Content-Type", "application/json; charset=utf-8")
-		req.Header.Add("Accept", "application/vnd.github.v3+json")
+		for uri != "" && len(issues) < opts.limit() {
+			results, next, err := c.searchPage(ctx, uri, staleness)
+			if err != nil {
+				return nil, err
+			}
 
-		if authToken := getAuthToken(c.host); authToken != "" {
-			req.Header.Add("Authorization", fmt.Sprintf("token %s", authToken))
-		}
+			// total count and incomplete results are reported
+			// on every page so only take them from the first
+			if len(issues) == 0 {
+				total = results.Total
+			}
+			out.IncompleteResults = out.IncompleteResults || results.IncompleteResults
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("doing http request: %w", err)
+			issues = append(issues, results.Items...)
+
+			// guard against an infinite loop if GitHub
+			// keeps returning a next link with no items
+			if len(results.Items) == 0 {
+				break
+			}
+
+			uri = next
 		}
 
-		// TODO: stream this?
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("reading response body: %w", err)
+		if len(issues) > opts.limit() {
+			issues = issues[:opts.limit()]
 		}
 
-		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-			return nil, fmt.Errorf("request failed with status %q: %s", resp.Status, bodyBytes)
+		if len(issues) < min(total, SearchResultCeiling) {
+			out.IncompleteResults = true
 		}
 
-		results := &search.IssuesResult{}
+		out.TotalCount += total
+		out.Issues = append(out.Issues, issues...)
+	}
 
-		err = json.Unmarshal(bodyBytes, results)
+	if opts.Enrich.enabled() {
+		refs := []PullRequestRef{}
+		for _, issue := range out.Issues {
+			if ref, ok := pullRequestRef(issue); ok {
+				refs = append(refs, ref)
+			}
+		}
+
+		details, err := c.PullRequestDetails(ctx, opts.Enrich, refs, staleness)
 		if err != nil {
-			return nil, fmt.Errorf("unmarshalling results: %w", err)
+			return nil, err
 		}
+		out.PullRequestDetails = details
+	}
 
-		out = append(out, results.Items...)
+	out.StaleSince = staleness.Since()
+	return out, nil
+}
 
+// pullRequestRef returns the reference to the pull request
+// for an issue returned by the search API, or false if the
+// issue is not a pull request.
+func pullRequestRef(issue search.Issue) (PullRequestRef, bool) {
+	if !issue.IsPullRequest() {
+		return PullRequestRef{}, false
 	}
 
-	return out, nil
+	_, repo, _ := strings.Cut(issue.RepositoryURL, "/repos/")
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return PullRequestRef{}, false
+	}
+
+	return PullRequestRef{Owner: owner, Repo: name, Number: issue.Number}, true
+}
+
+func (c *Client) searchPage(ctx context.Context, uri string, staleness *httpcache.Staleness) (*search.IssuesResult, string, error) {
+	results := &search.IssuesResult{}
+
+	next, err := c.get(ctx, uri, results, staleness)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return results, next, nil
+}
+
+// get fetches the JSON response from the REST API at uri into into,
+// returning the URL of the next page of results if there is one.
+func (c *Client) get(ctx context.Context, uri string, into any, staleness *httpcache.Staleness) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/vnd.github.v3+json")
+
+	if authToken := getAuthToken(c.host); authToken != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("token %s", authToken))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("doing http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	staleness.Observe(resp)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("request failed with status %q: %s", resp.Status, bodyBytes)
+	}
+
+	err = json.Unmarshal(bodyBytes, into)
+	if err != nil {
+		return "", fmt.Errorf("unmarshalling results: %w", err)
+	}
+
+	return nextLink(resp.Header.Get("Link")), nil
+}
+
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextLink returns the URL of the next page from
+// an RFC 8288 Link header, or an empty string if
+// there is no next page.
+func nextLink(header string) string {
+	matches := linkNextRegex.FindStringSubmatch(header)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
 }
 
 func getAuthToken(host string) string {
@@ -0,0 +1,47 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// IssueComment is a comment on an issue or pull request.
+type IssueComment struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Comments fetches all comments on an issue or pull request, oldest first.
+// Review comments on the diff of a pull request are not included.
+func (c *Client) Comments(ctx context.Context, repository string, number int, staleness *httpcache.Staleness) ([]IssueComment, error) {
+	qs := url.Values{}
+	qs.Set("per_page", strconv.Itoa(MaxPerPage))
+
+	uri := fmt.Sprintf("%s/repos/%s/issues/%d/comments?%s", c.endpoints.REST, repository, number, qs.Encode())
+	comments := []IssueComment{}
+
+	for uri != "" {
+		page := []IssueComment{}
+		next, err := c.get(ctx, uri, &page, staleness)
+		if err != nil {
+			return nil, fmt.Errorf("fetching comments for %s#%d: %w", repository, number, err)
+		}
+
+		comments = append(comments, page...)
+		if len(page) == 0 {
+			break
+		}
+
+		uri = next
+	}
+
+	return comments, nil
+}
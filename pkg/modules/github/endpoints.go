@@ -0,0 +1,76 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// Endpoints are the base URLs of the GitHub APIs for a host.
+type Endpoints struct {
+	// REST is the base URL of the REST API, without a trailing slash.
+	REST string
+
+	// GraphQL is the URL of the GraphQL API.
+	GraphQL string
+}
+
+// EndpointsForHost returns the API endpoints for a GitHub host.
+// GitHub.com and GHE.com tenancies serve the APIs at
+// https://api.<host>, whereas GitHub Enterprise Server serves the
+// REST API at https://<host>/api/v3 and the GraphQL API at
+// https://<host>/api/graphql.
+func EndpointsForHost(host string) Endpoints {
+	scheme, hostname := splitHost(host)
+
+	if auth.IsEnterprise(hostname) {
+		return Endpoints{
+			REST:    fmt.Sprintf("%s://%s/api/v3", scheme, hostname),
+			GraphQL: fmt.Sprintf("%s://%s/api/graphql", scheme, hostname),
+		}
+	}
+
+	hostname = auth.NormalizeHostname(hostname)
+	if hostname == "github.localhost" {
+		scheme = "http"
+	}
+
+	return Endpoints{
+		REST:    fmt.Sprintf("%s://api.%s", scheme, hostname),
+		GraphQL: fmt.Sprintf("%s://api.%s/graphql", scheme, hostname),
+	}
+}
+
+// EndpointsForAPIURL returns the API endpoints given the base URL of
+// the REST API. The GraphQL endpoint is derived from it, so for
+// GitHub Enterprise Server https://<host>/api/v3 results in a GraphQL
+// endpoint of https://<host>/api/graphql and otherwise the GraphQL
+// endpoint is <apiURL>/graphql.
+func EndpointsForAPIURL(apiURL string) Endpoints {
+	rest := strings.TrimSuffix(apiURL, "/")
+
+	if base, ok := strings.CutSuffix(rest, "/api/v3"); ok {
+		return Endpoints{
+			REST:    rest,
+			GraphQL: base + "/api/graphql",
+		}
+	}
+
+	return Endpoints{
+		REST:    rest,
+		GraphQL: rest + "/graphql",
+	}
+}
+
+// splitHost splits a host that may optionally include a scheme
+// (i.e "https://github.example.com") into its scheme and hostname.
+// The scheme defaults to https.
+func splitHost(host string) (string, string) {
+	scheme := "https"
+	if before, after, ok := strings.Cut(host, "://"); ok {
+		scheme = before
+		host = after
+	}
+	return scheme, strings.TrimSuffix(host, "/")
+}
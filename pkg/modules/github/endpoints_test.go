@@ -0,0 +1,243 @@
+package github
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+func TestEndpointsForHost(t *testing.T) {
+	for _, tc := range []struct {
+		host string
+		want Endpoints
+	}{
+		{
+			host: "github.com",
+			want: Endpoints{REST: "https://api.github.com", GraphQL: "https://api.github.com/graphql"},
+		},
+		{
+			host: "octo.ghe.com",
+			want: Endpoints{REST: "https://api.octo.ghe.com", GraphQL: "https://api.octo.ghe.com/graphql"},
+		},
+		{
+			host: "github.example.com",
+			want: Endpoints{REST: "https://github.example.com/api/v3", GraphQL: "https://github.example.com/api/graphql"},
+		},
+		{
+			host: "http://github.example.com/",
+			want: Endpoints{REST: "http://github.example.com/api/v3", GraphQL: "http://github.example.com/api/graphql"},
+		},
+	} {
+		t.Run(tc.host, func(t *testing.T) {
+			if got := EndpointsForHost(tc.host); got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEndpointsForAPIURL(t *testing.T) {
+	for _, tc := range []struct {
+		apiURL string
+		want   Endpoints
+	}{
+		{
+			apiURL: "https://github.example.com/api/v3/",
+			want:   Endpoints{REST: "https://github.example.com/api/v3", GraphQL: "https://github.example.com/api/graphql"},
+		},
+		{
+			apiURL: "https://proxy.example.com/github",
+			want:   Endpoints{REST: "https://proxy.example.com/github", GraphQL: "https://proxy.example.com/github/graphql"},
+		},
+	} {
+		t.Run(tc.apiURL, func(t *testing.T) {
+			if got := EndpointsForAPIURL(tc.apiURL); got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+// fakeGitHub is a GitHub API served over TLS with a self-signed
+// certificate that records the paths of the requests it receives.
+type fakeGitHub struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	paths []string
+}
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	t.Helper()
+
+	setTestToken(t)
+	t.Setenv(CABundleEnv, "")
+
+	fake := &fakeGitHub{}
+	fake.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		fake.paths = append(fake.paths, r.URL.Path)
+		fake.mu.Unlock()
+
+		if auth := r.Header.Get("Authorization"); !strings.HasSuffix(auth, " test-token") {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/search/issues"):
+			_, _ = io.WriteString(w, `{
+				"total_count": 1,
+				"incomplete_results": false,
+				"items": [{
+					"number": 1,
+					"title": "Add endpoints",
+					"html_url": "https://github.example.com/octo/repo/pull/1",
+					"repository_url": "https://github.example.com/api/v3/repos/octo/repo",
+					"pull_request": {"html_url": "https://github.example.com/octo/repo/pull/1"}
+				}]
+			}`)
+		case strings.HasSuffix(r.URL.Path, "graphql"):
+			_, _ = io.WriteString(w, `{"data": {"pr0": {"pullRequest": {"isDraft": true}}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+// setTestToken configures "test-token" as the token for every
+// host, so that it is read from the environment rather than the
+// configuration of the GitHub CLI.
+func setTestToken(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	t.Setenv("GH_TOKEN", "test-token")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "test-token")
+}
+
+func (f *fakeGitHub) requestedPaths() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.paths...)
+}
+
+// caBundle writes the self-signed certificate of the
+// server to a PEM encoded CA bundle and returns its path.
+func (f *fakeGitHub) caBundle(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: f.Certificate().Raw})
+	if err := os.WriteFile(path, bundle, 0o600); err != nil {
+		t.Fatalf("writing CA bundle: %v", err)
+	}
+	return path
+}
+
+// callSearch calls github.search(...) with the keyword arguments.
+func callSearch(t *testing.T, kwargs map[string]string) (*modules.Results, error) {
+	t.Helper()
+
+	cache := httpcache.New(httpcache.Options{Dir: t.TempDir()})
+	fn := starlark.NewBuiltin(SearchAttr, SearchBuiltin(cache))
+
+	starKwargs := []starlark.Tuple{
+		{starlark.String("query"), starlark.String("is:open")},
+		{starlark.String("enrich"), starlark.NewList([]starlark.Value{starlark.String(EnrichReviews)})},
+	}
+	for key, value := range kwargs {
+		starKwargs = append(starKwargs, starlark.Tuple{starlark.String(key), starlark.String(value)})
+	}
+
+	thread := &starlark.Thread{Name: "test"}
+	value, err := starlark.Call(thread, fn, nil, starKwargs)
+	if err != nil {
+		return nil, err
+	}
+	return value.(*modules.Results), nil
+}
+
+func TestSearchEnterpriseServer(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	results, err := callSearch(t, map[string]string{
+		"host":      fake.URL,
+		"ca_bundle": fake.caBundle(t),
+	})
+	if err != nil {
+		t.Fatalf("searching: %v", err)
+	}
+	if results.Len() != 1 {
+		t.Fatalf("got %d results, want 1", results.Len())
+	}
+
+	want := []string{"/api/v3/search/issues", "/api/graphql"}
+	if got := fake.requestedPaths(); !slices.Equal(got, want) {
+		t.Errorf("got requests to %v, want %v", got, want)
+	}
+}
+
+func TestSearchAPIURL(t *testing.T) {
+	fake := newFakeGitHub(t)
+
+	_, err := callSearch(t, map[string]string{
+		"host":      "github.example.com",
+		"api_url":   fake.URL + "/proxy/github",
+		"ca_bundle": fake.caBundle(t),
+	})
+	if err != nil {
+		t.Fatalf("searching: %v", err)
+	}
+
+	want := []string{"/proxy/github/search/issues", "/proxy/github/graphql"}
+	if got := fake.requestedPaths(); !slices.Equal(got, want) {
+		t.Errorf("got requests to %v, want %v", got, want)
+	}
+}
+
+func TestSearchCABundle(t *testing.T) {
+	t.Run("from the ca_bundle parameter", func(t *testing.T) {
+		fake := newFakeGitHub(t)
+
+		if _, err := callSearch(t, map[string]string{"host": fake.URL, "ca_bundle": fake.caBundle(t)}); err != nil {
+			t.Errorf("expected the CA bundle to be trusted: %v", err)
+		}
+	})
+
+	t.Run("from the environment", func(t *testing.T) {
+		fake := newFakeGitHub(t)
+		t.Setenv(CABundleEnv, fake.caBundle(t))
+
+		if _, err := callSearch(t, map[string]string{"host": fake.URL}); err != nil {
+			t.Errorf("expected the CA bundle to be trusted: %v", err)
+		}
+	})
+
+	t.Run("without a CA bundle", func(t *testing.T) {
+		fake := newFakeGitHub(t)
+
+		if _, err := callSearch(t, map[string]string{"host": fake.URL}); err == nil {
+			t.Error("expected the self-signed certificate to be untrusted")
+		}
+		if got := fake.requestedPaths(); len(got) != 0 {
+			t.Errorf("expected no requests to be handled, got %v", got)
+		}
+	})
+}
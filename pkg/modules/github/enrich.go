@@ -0,0 +1,333 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// enrichBatchSize is the maximum number of pull requests
+// fetched in a single GraphQL query.
+const enrichBatchSize = 50
+
+// EnrichOptions configures which additional data is fetched
+// for pull requests using the GraphQL API.
+type EnrichOptions struct {
+	// Reviews fetches the review decision, requested
+	// reviewers and latest reviews of each pull request.
+	Reviews bool
+
+	// Checks fetches the combined status of the CI
+	// checks for the head commit of each pull request.
+	Checks bool
+}
+
+func (eo EnrichOptions) enabled() bool {
+	return eo.Reviews || eo.Checks
+}
+
+// PullRequestRef identifies a pull request.
+type PullRequestRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// PullRequestDetails is information about a pull
+// request that the search API does not return.
+type PullRequestDetails struct {
+	Draft     bool   `json:"draft"`
+	HeadRef   string `json:"head_ref"`
+	BaseRef   string `json:"base_ref"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+
+	// Mergeable is one of MERGEABLE, CONFLICTING or UNKNOWN.
+	Mergeable string `json:"mergeable"`
+
+	// ReviewDecision is one of APPROVED, CHANGES_REQUESTED or
+	// REVIEW_REQUIRED, or empty if reviews weren't fetched or the
+	// repository doesn't require reviews.
+	ReviewDecision     string   `json:"review_decision,omitempty"`
+	RequestedReviewers []string `json:"requested_reviewers,omitempty"`
+	Reviews            []Review `json:"reviews,omitempty"`
+
+	// CheckStatus is one of SUCCESS, FAILURE, PENDING, ERROR or
+	// EXPECTED, or empty if checks weren't fetched or the head
+	// commit has no checks.
+	CheckStatus string `json:"check_status,omitempty"`
+}
+
+// Review is the latest review of a pull request by a reviewer.
+type Review struct {
+	Author      string    `json:"author"`
+	State       string    `json:"state"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// PullRequestDetails fetches details about the pull requests using
+// batched GraphQL queries. Pull requests that can't be found are
+// omitted from the returned map.
+func (c *Client) PullRequestDetails(ctx context.Context, opts EnrichOptions, refs []PullRequestRef, staleness *httpcache.Staleness) (map[PullRequestRef]*PullRequestDetails, error) {
+	out := map[PullRequestRef]*PullRequestDetails{}
+
+	for batch := range chunk(refs, enrichBatchSize) {
+		query, variables := pullRequestsQuery(opts, batch)
+
+		data := map[string]*struct {
+			PullRequest *graphqlPullRequest `json:"pullRequest"`
+		}{}
+
+		err := c.graphql(ctx, query, variables, &data, staleness)
+		if err != nil {
+			return nil, fmt.Errorf("fetching pull request details: %w", err)
+		}
+
+		for i, ref := range batch {
+			repo := data[fmt.Sprintf("pr%d", i)]
+			if repo == nil || repo.PullRequest == nil {
+				continue
+			}
+			out[ref] = repo.PullRequest.details()
+		}
+	}
+
+	return out, nil
+}
+
+func chunk[T any](items []T, size int) func(yield func([]T) bool) {
+	return func(yield func([]T) bool) {
+		for start := 0; start < len(items); start += size {
+			if !yield(items[start:min(start+size, len(items))]) {
+				return
+			}
+		}
+	}
+}
+
+const pullRequestFields = `
+  isDraft
+  headRefName
+  baseRefName
+  additions
+  deletions
+  mergeable
+`
+
+const reviewFields = `
+  reviewDecision
+  reviewRequests(first: 20) {
+    nodes {
+      requestedReviewer {
+        ... on User { login }
+        ... on Bot { login }
+        ... on Mannequin { login }
+        ... on Team { slug }
+      }
+    }
+  }
+  latestReviews(first: 20) {
+    nodes {
+      author { login }
+      state
+      submittedAt
+    }
+  }
+`
+
+const checkFields = `
+  commits(last: 1) {
+    nodes {
+      commit {
+        statusCheckRollup { state }
+      }
+    }
+  }
+`
+
+// pullRequestsQuery builds a GraphQL query that fetches all of the
+// pull requests in a single request, using an alias per pull request.
+func pullRequestsQuery(opts EnrichOptions, refs []PullRequestRef) (string, map[string]any) {
+	fragment := pullRequestFields
+	if opts.Reviews {
+		fragment += reviewFields
+	}
+	if opts.Checks {
+		fragment += checkFields
+	}
+
+	params := []string{}
+	selections := []string{}
+	variables := map[string]any{}
+
+	for i, ref := range refs {
+		params = append(params, fmt.Sprintf("$owner%d: String!, $repo%d: String!, $number%d: Int!", i, i, i))
+		selections = append(selections, fmt.Sprintf("  pr%d: repository(owner: $owner%d, name: $repo%d) { pullRequest(number: $number%d) { ...details } }", i, i, i, i))
+		variables[fmt.Sprintf("owner%d", i)] = ref.Owner
+		variables[fmt.Sprintf("repo%d", i)] = ref.Repo
+		variables[fmt.Sprintf("number%d", i)] = ref.Number
+	}
+
+	query := fmt.Sprintf("query(%s) {\n%s\n}\n\nfragment details on PullRequest {%s}\n",
+		strings.Join(params, ", "),
+		strings.Join(selections, "\n"),
+		fragment,
+	)
+
+	return query, variables
+}
+
+type graphqlPullRequest struct {
+	IsDraft        bool   `json:"isDraft"`
+	HeadRefName    string `json:"headRefName"`
+	BaseRefName    string `json:"baseRefName"`
+	Additions      int    `json:"additions"`
+	Deletions      int    `json:"deletions"`
+	Mergeable      string `json:"mergeable"`
+	ReviewDecision string `json:"reviewDecision"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer struct {
+				Login string `json:"login"`
+				Slug  string `json:"slug"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	LatestReviews struct {
+		Nodes []struct {
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			State       string    `json:"state"`
+			SubmittedAt time.Time `json:"submittedAt"`
+		} `json:"nodes"`
+	} `json:"latestReviews"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+func (pr *graphqlPullRequest) details() *PullRequestDetails {
+	details := &PullRequestDetails{
+		Draft:          pr.IsDraft,
+		HeadRef:        pr.HeadRefName,
+		BaseRef:        pr.BaseRefName,
+		Additions:      pr.Additions,
+		Deletions:      pr.Deletions,
+		Mergeable:      pr.Mergeable,
+		ReviewDecision: pr.ReviewDecision,
+	}
+
+	for _, node := range pr.ReviewRequests.Nodes {
+		reviewer := node.RequestedReviewer.Login
+		if reviewer == "" {
+			reviewer = node.RequestedReviewer.Slug
+		}
+		if reviewer != "" {
+			details.RequestedReviewers = append(details.RequestedReviewers, reviewer)
+		}
+	}
+
+	for _, node := range pr.LatestReviews.Nodes {
+		details.Reviews = append(details.Reviews, Review{
+			Author:      node.Author.Login,
+			State:       node.State,
+			SubmittedAt: node.SubmittedAt,
+		})
+	}
+
+	for _, node := range pr.Commits.Nodes {
+		if node.Commit.StatusCheckRollup != nil {
+			details.CheckStatus = node.Commit.StatusCheckRollup.State
+		}
+	}
+
+	return details
+}
+
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphql executes a GraphQL query. Queries are idempotent,
+// so their responses are cached like search responses.
+func (c *Client) graphql(ctx context.Context, query string, variables map[string]any, into any, staleness *httpcache.Staleness) error {
+	body, err := json.Marshal(graphqlRequest{Query: query, Variables: variables})
+	if err != nil {
+		return fmt.Errorf("marshalling query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(httpcache.WithIdempotent(ctx), http.MethodPost, c.endpoints.GraphQL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json; charset=utf-8")
+	req.Header.Add("Accept", "application/json")
+
+	if authToken := getAuthToken(c.host); authToken != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("bearer %s", authToken))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	staleness.Observe(resp)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("request failed with status %q: %s", resp.Status, bodyBytes)
+	}
+
+	result := &graphqlResponse{}
+	if err := json.Unmarshal(bodyBytes, result); err != nil {
+		return fmt.Errorf("unmarshalling response: %w", err)
+	}
+
+	// pull requests that can't be found are reported as NOT_FOUND
+	// errors alongside partial data, which isn't fatal
+	for _, gqlErr := range result.Errors {
+		if gqlErr.Type != "NOT_FOUND" {
+			return fmt.Errorf("graphql error: %s", gqlErr.Message)
+		}
+	}
+
+	if len(result.Data) == 0 || string(result.Data) == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal(result.Data, into); err != nil {
+		return fmt.Errorf("unmarshalling results: %w", err)
+	}
+
+	return nil
+}
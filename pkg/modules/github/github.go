@@ -2,8 +2,10 @@ package github
 
 import (
 	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
 )
 
-func New() (string, starlark.Value) {
-	return "github", &Module{}
+func New(cache *httpcache.Cache) (string, starlark.Value) {
+	return "github", &Module{Cache: cache}
 }
@@ -4,10 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/cli/cli/v2/pkg/search"
 	"go.starlark.net/starlark"
 
+	"github.com/everettraven/wranglr/pkg/httpcache"
 	"github.com/everettraven/wranglr/pkg/modules"
 )
 
@@ -15,7 +20,9 @@ import (
 // - Need to figure out how to make this information available to the
 // Starlark LSP.
 
-type Module struct{}
+type Module struct {
+	Cache *httpcache.Cache
+}
 
 func (m *Module) String() string        { return "github" }
 func (m *Module) Type() string          { return "Module" }
@@ -23,12 +30,34 @@ func (m *Module) Truth() starlark.Bool  { return starlark.False }
 func (m *Module) Freeze()               {}
 func (m *Module) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
 
-const SearchAttr = "search"
+const (
+	SearchAttr      = "search"
+	SearchAsyncAttr = "search_async"
+)
+
+// CABundleEnv is the environment variable used to configure the
+// path of a PEM encoded CA bundle to trust when the ca_bundle
+// parameter isn't set. Useful for GitHub Enterprise Server instances
+// using certificates signed by an internal certificate authority.
+const CABundleEnv = "WRANGLR_GITHUB_CA_BUNDLE"
+
+const (
+	EnrichReviews = "reviews"
+	EnrichChecks  = "checks"
+)
 
 func (m *Module) Attr(name string) (starlark.Value, error) {
 	switch name {
 	case SearchAttr:
-		return starlark.NewBuiltin(SearchAttr, SearchBuiltin()), nil
+		return starlark.NewBuiltin(SearchAttr, SearchBuiltin(m.Cache)), nil
+	case SearchAsyncAttr:
+		return starlark.NewBuiltin(SearchAsyncAttr, SearchAsyncBuiltin(m.Cache)), nil
+	case AddLabelsAttr:
+		return starlark.NewBuiltin(AddLabelsAttr, AddLabelsBuiltin()), nil
+	case CommentAttr:
+		return starlark.NewBuiltin(CommentAttr, CommentBuiltin()), nil
+	case SetAssigneesAttr:
+		return starlark.NewBuiltin(SetAssigneesAttr, SetAssigneesBuiltin()), nil
 	default:
 		return nil, fmt.Errorf("unknown attribute %q", name)
 	}
@@ -37,45 +66,132 @@ func (m *Module) Attr(name string) (starlark.Value, error) {
 func (m *Module) AttrNames() []string {
 	return []string{
 		SearchAttr,
+		SearchAsyncAttr,
+		AddLabelsAttr,
+		CommentAttr,
+		SetAssigneesAttr,
 	}
 }
 
-func SearchBuiltin() modules.BuiltinFunc {
-	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+func SearchBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.SyncBuiltin(searchFetch(cache))
+}
+
+func SearchAsyncBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.AsyncBuiltin(searchFetch(cache))
+}
+
+func searchFetch(cache *httpcache.Cache) modules.FetchBuilder {
+	return func(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (modules.FetchFunc, error) {
 		var host starlark.String
 		var query starlark.String
 		var group starlark.String
+		var limit int
+		var perPage int
+		var apiURL starlark.String
+		var caBundle starlark.String
+		var enrich *starlark.List
+
+		var cacheTTL modules.OptionalDuration
 
-		err := starlark.UnpackArgs(SearchAttr, args, kwargs,
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
 			"host?", &host,
 			"query", &query,
 			"group?", &group,
+			"limit?", &limit,
+			"per_page?", &perPage,
+			"cache_ttl?", &cacheTTL,
+			"api_url?", &apiURL,
+			"ca_bundle?", &caBundle,
+			"enrich?", &enrich,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		opts := SearchOptions{Limit: limit, PerPage: perPage}
+		if enrich != nil {
+			for elem := range enrich.Elements() {
+				enrichment, ok := starlark.AsString(elem)
+				if !ok {
+					return nil, fmt.Errorf("%s: enrich must be a list of strings but contained type %q", fn.Name(), elem.Type())
+				}
+
+				switch enrichment {
+				case EnrichReviews:
+					opts.Enrich.Reviews = true
+				case EnrichChecks:
+					opts.Enrich.Checks = true
+				default:
+					return nil, fmt.Errorf("%s: enrich must only contain [%s, %s] but contained %q", fn.Name(), EnrichReviews, EnrichChecks, enrichment)
+				}
+			}
+		}
+
 		hostValue := "github.com"
 		if host.GoString() != "" {
 			hostValue = host.GoString()
 		}
 
-		ghClient := NewClient(hostValue)
+		clientOpts := []ClientOption{}
+		if apiURL.GoString() != "" {
+			clientOpts = append(clientOpts, WithEndpoints(EndpointsForAPIURL(apiURL.GoString())))
+		}
 
-		issues, err := ghClient.Issues(context.TODO(), query.GoString())
-		if err != nil {
-			return nil, err
+		caBundleValue := os.Getenv(CABundleEnv)
+		if caBundle.GoString() != "" {
+			caBundleValue = caBundle.GoString()
 		}
 
-		return issuesToStarlark(group.GoString(), issues...), nil
+		transport, err := modules.CABundleTransport(caBundleValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+		}
+		httpClient := &http.Client{Transport: cache.Transport(transport)}
+
+		return func(ctx context.Context) (starlark.Value, error) {
+			if cacheTTL.Set {
+				ctx = httpcache.WithTTL(ctx, cacheTTL.Duration)
+			}
+
+			ghClient := NewClient(hostValue, httpClient, clientOpts...)
+
+			results, err := ghClient.Issues(ctx, opts, query.GoString())
+			if err != nil {
+				return nil, err
+			}
+
+			return modules.NewResults(
+				issuesToStarlark(group.GoString(), results, ghClient),
+				results.TotalCount,
+				results.IncompleteResults,
+			), nil
+		}, nil
 	}
 }
 
 type Item struct {
-	issue    search.Issue
-	status   string
-	priority int64
-	group    string
+	modules.BaseItem
+	issue search.Issue
+
+	// details is nil unless the item is a pull
+	// request and enrichment was requested.
+	details *PullRequestDetails
+
+	// client is the client that fetched the item, used
+	// for fetching additional data about it on demand.
+	client *Client
+}
+
+var _ modules.Commentable = (*Item)(nil)
+
+// NewItem creates an item for an issue or pull request returned by the
+// GitHub search API. Items created this way can't fetch their comments.
+func NewItem(issue search.Issue, group string, staleSince time.Time) *Item {
+	return &Item{
+		BaseItem: modules.NewBaseItem(group, staleSince),
+		issue:    issue,
+	}
 }
 
 type ItemType string
@@ -85,31 +201,118 @@ const (
 	ItemTypePullRequest ItemType = "pullrequest"
 )
 
-func (i *Item) Priority() int64 {
-	return i.priority
+func (i *Item) URL() string {
+	return i.issue.URL
+}
+
+func (i *Item) Issue() search.Issue {
+	return i.issue
+}
+
+func (i *Item) Source() string {
+	return "github"
+}
+
+// ID returns an identifier for the item of the form "owner/repo#number".
+func (i *Item) ID() string {
+	return fmt.Sprintf("%s#%d", i.Repository(), i.issue.Number)
+}
+
+func (i *Item) Title() string {
+	return i.issue.Title
 }
 
-func (i *Item) Status() string {
-	if i.status == "" {
-		return "Unknown"
+func (i *Item) Body() string {
+	return i.issue.Body
+}
+
+func (i *Item) Author() string {
+	return i.issue.Author.Login
+}
+
+func (i *Item) Assignees() []string {
+	assignees := []string{}
+	for _, assignee := range i.issue.Assignees {
+		assignees = append(assignees, assignee.Login)
 	}
+	return assignees
+}
 
-	return i.status
+func (i *Item) Labels() []string {
+	labels := []string{}
+	for _, label := range i.issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	return labels
 }
 
-func (i *Item) URL() string {
-	return i.issue.URL
+func (i *Item) CreatedAt() time.Time {
+	return i.issue.CreatedAt
 }
 
-func (i *Item) Group() string {
-	if i.group == "" {
-		return "Unknown"
+func (i *Item) UpdatedAt() time.Time {
+	return i.issue.UpdatedAt
+}
+
+// Client returns the client used to fetch the item, which
+// can be used to make further requests about the item.
+func (i *Item) Client() *Client {
+	return i.client
+}
+
+// Comments fetches the comments on the issue or pull request.
+func (i *Item) Comments(ctx context.Context) (*modules.CommentsResult, error) {
+	// items built with NewItem aren't fetched using a client
+	if i.client == nil {
+		return nil, errors.New("comments are not available for this item")
+	}
+
+	staleness := &httpcache.Staleness{}
+	comments, err := i.client.Comments(ctx, i.Repository(), i.issue.Number, staleness)
+	if err != nil {
+		return nil, err
 	}
-	return i.group
+
+	out := &modules.CommentsResult{
+		Comments:   []*modules.Comment{},
+		StaleSince: staleness.Since(),
+	}
+	for _, comment := range comments {
+		out.Comments = append(out.Comments, &modules.Comment{
+			Author:    comment.User.Login,
+			Body:      comment.Body,
+			CreatedAt: comment.CreatedAt,
+		})
+	}
+	return out, nil
 }
 
-func (i *Item) Issue() search.Issue {
-	return i.issue
+// Repository returns the "owner/repo" name of
+// the repository the item belongs to.
+func (i *Item) Repository() string {
+	_, repo, _ := strings.Cut(i.issue.RepositoryURL, "/repos/")
+	return repo
+}
+
+// PullRequestDetails returns the details of the pull request, or nil
+// if the item is not a pull request or enrichment was not requested.
+func (i *Item) PullRequestDetails() *PullRequestDetails {
+	return i.details
+}
+
+// RawItem is the raw representation of an item.
+type RawItem struct {
+	search.Issue
+	PullRequestDetails *PullRequestDetails `json:"pull_request_details,omitempty"`
+}
+
+// Raw returns the item as returned by the GitHub search API,
+// along with the pull request details if they were fetched.
+func (i *Item) Raw() any {
+	return RawItem{
+		Issue:              i.issue,
+		PullRequestDetails: i.details,
+	}
 }
 
 func (i *Item) String() string { return "todo" }
@@ -125,37 +328,29 @@ func (i *Item) Freeze()               {}
 func (i *Item) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
 
 func (i *Item) Attr(name string) (starlark.Value, error) {
+	if val, err := i.BaseItem.Attr(name); val != nil || err != nil {
+		return val, err
+	}
+
 	switch name {
-	case "status":
-		return starlark.String(i.Status()), nil
-	case "priority":
-		return starlark.MakeInt64(i.Priority()), nil
-	case "group":
-		return starlark.String(i.Group()), nil
 	case "assignees":
-		elems := []starlark.Value{}
-		for _, assignee := range i.issue.Assignees {
-			elems = append(elems, starlark.String(assignee.Login))
-		}
-		return starlark.NewList(elems), nil
+		return modules.StringList(i.Assignees()), nil
 	case "author":
-		return starlark.String(i.issue.Author.Login), nil
+		return starlark.String(i.Author()), nil
 	case "author_association":
 		return starlark.String(i.issue.AuthorAssociation), nil
 	case "body":
 		return starlark.String(i.issue.Body), nil
 	case "closed_at":
-		return starlark.String(i.issue.ClosedAt.String()), nil
+		return modules.Time(i.issue.ClosedAt), nil
 	case "comments":
 		return starlark.MakeInt(i.issue.CommentsCount), nil
 	case "created_at":
-		return starlark.String(i.issue.CreatedAt.String()), nil
+		return modules.Time(i.issue.CreatedAt), nil
+	case modules.FetchCommentsAttr:
+		return modules.FetchCommentsBuiltin(i), nil
 	case "labels":
-		elems := []starlark.Value{}
-		for _, label := range i.issue.Labels {
-			elems = append(elems, starlark.String(label.Name))
-		}
-		return starlark.NewList(elems), nil
+		return modules.StringList(i.Labels()), nil
 	case "locked":
 		return starlark.Bool(i.issue.IsLocked), nil
 	case "number":
@@ -169,7 +364,7 @@ func (i *Item) Attr(name string) (starlark.Value, error) {
 			return starlark.None, err
 		}
 
-		err = dict.SetKey(starlark.String("merged_at"), starlark.String(i.issue.PullRequest.MergedAt.String()))
+		err = dict.SetKey(starlark.String("merged_at"), modules.Time(i.issue.PullRequest.MergedAt))
 		if err != nil {
 			return starlark.None, err
 		}
@@ -182,17 +377,20 @@ func (i *Item) Attr(name string) (starlark.Value, error) {
 	case "title":
 		return starlark.String(i.issue.Title), nil
 	case "updated_at":
-		return starlark.String(i.issue.UpdatedAt.String()), nil
+		return modules.Time(i.issue.UpdatedAt), nil
+	case modules.AgeAttr:
+		return modules.Since(i.CreatedAt()), nil
+	case modules.SinceUpdateAttr:
+		return modules.Since(i.UpdatedAt()), nil
+	case "review_decision", "requested_reviewers", "reviews", "check_status", "mergeable", "draft", "head_ref", "base_ref", "additions", "deletions":
+		return i.detailsAttr(name)
 	default:
 		return nil, fmt.Errorf("unknown attribute %q", name)
 	}
 }
 
 func (i *Item) AttrNames() []string {
-	return []string{
-		"status",
-		"priority",
-		"group",
+	return append(i.BaseItem.AttrNames(),
 		"assignees",
 		"author",
 		"author_association",
@@ -200,6 +398,7 @@ func (i *Item) AttrNames() []string {
 		"closed_at",
 		"comments",
 		"created_at",
+		modules.FetchCommentsAttr,
 		"labels",
 		"locked",
 		"number",
@@ -208,41 +407,93 @@ func (i *Item) AttrNames() []string {
 		"state_reason",
 		"title",
 		"updated_at",
-	}
+		modules.AgeAttr,
+		modules.SinceUpdateAttr,
+		"review_decision",
+		"requested_reviewers",
+		"reviews",
+		"check_status",
+		"mergeable",
+		"draft",
+		"head_ref",
+		"base_ref",
+		"additions",
+		"deletions",
+	)
 }
 
+// SetField sets the wranglr-specific field or custom field with the provided name.
 func (i *Item) SetField(name string, val starlark.Value) error {
+	return i.BaseItem.SetItemField(i, name, val)
+}
+
+// detailsAttr returns the value of an attribute backed by the pull
+// request details. They are None unless the item is a pull request
+// and enrichment was requested.
+func (i *Item) detailsAttr(name string) (starlark.Value, error) {
+	if i.details == nil {
+		return starlark.None, nil
+	}
+
 	switch name {
-	case "status":
-		i.status = val.String()
-		return nil
-	case "priority":
-		intType, ok := val.(starlark.Int)
-		if !ok {
-			return fmt.Errorf("priority must be an integer but was attempted to be set to type %q", val.Type())
-		}
-		i64, ok := intType.Int64()
-		if !ok {
-			return errors.New("priority must be a valid int64, but was not")
+	case "review_decision":
+		return optionalString(i.details.ReviewDecision), nil
+	case "requested_reviewers":
+		return modules.StringList(i.details.RequestedReviewers), nil
+	case "reviews":
+		elems := []starlark.Value{}
+		for _, review := range i.details.Reviews {
+			dict := starlark.NewDict(3)
+			for _, kv := range []starlark.Tuple{
+				{starlark.String("author"), starlark.String(review.Author)},
+				{starlark.String("state"), starlark.String(review.State)},
+				{starlark.String("submitted_at"), modules.Time(review.SubmittedAt)},
+			} {
+				if err := dict.SetKey(kv[0], kv[1]); err != nil {
+					return starlark.None, err
+				}
+			}
+			elems = append(elems, dict)
 		}
-		i.priority = i64
-		return nil
-	case "group":
-		i.group = val.String()
-		return nil
+		return starlark.NewList(elems), nil
+	case "check_status":
+		return optionalString(i.details.CheckStatus), nil
+	case "mergeable":
+		return starlark.String(i.details.Mergeable), nil
+	case "draft":
+		return starlark.Bool(i.details.Draft), nil
+	case "head_ref":
+		return starlark.String(i.details.HeadRef), nil
+	case "base_ref":
+		return starlark.String(i.details.BaseRef), nil
+	case "additions":
+		return starlark.MakeInt(i.details.Additions), nil
+	case "deletions":
+		return starlark.MakeInt(i.details.Deletions), nil
 	default:
-		return fmt.Errorf("cannot set field %q", name)
+		return nil, fmt.Errorf("unknown attribute %q", name)
 	}
 }
 
-func issuesToStarlark(group string, issues ...search.Issue) starlark.Value {
+func optionalString(str string) starlark.Value {
+	if str == "" {
+		return starlark.None
+	}
+	return starlark.String(str)
+}
+
+func issuesToStarlark(group string, results *SearchResult, client *Client) []starlark.Value {
 	elems := []starlark.Value{}
-	for _, issue := range issues {
-		elems = append(elems, &Item{
-			issue: issue,
-			group: group,
-		})
+	for _, issue := range results.Issues {
+		item := NewItem(issue, group, results.StaleSince)
+		item.client = client
+
+		if ref, ok := pullRequestRef(issue); ok {
+			item.details = results.PullRequestDetails[ref]
+		}
+
+		elems = append(elems, item)
 	}
 
-	return starlark.NewList(elems)
+	return elems
 }
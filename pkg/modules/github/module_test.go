@@ -0,0 +1,17 @@
+package github
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/search"
+)
+
+func TestCommentsWithoutClient(t *testing.T) {
+	item := NewItem(search.Issue{Number: 1}, "", time.Time{})
+
+	if _, err := item.Comments(context.Background()); err == nil {
+		t.Fatal("expected an error fetching comments without a client")
+	}
+}
@@ -0,0 +1,90 @@
+package gitlab
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// getAuthToken returns the token used to authenticate against the GitLab
+// instance at host. The token configured for the host in the GitLab CLI
+// (glab) is used first. Otherwise the WRANGLR_GITLAB_TOKEN and GITLAB_TOKEN
+// environment variables are used, but only for the host they are for, so
+// that a token for one instance is never sent to another.
+func getAuthToken(host string) string {
+	if token := glabToken(host); token != "" {
+		return token
+	}
+
+	if !strings.EqualFold(host, envTokenHost()) {
+		return ""
+	}
+
+	for _, env := range []string{"WRANGLR_GITLAB_TOKEN", "GITLAB_TOKEN"} {
+		if token := os.Getenv(env); token != "" {
+			return token
+		}
+	}
+
+	return ""
+}
+
+// envTokenHost returns the host that the token from the environment
+// is for, which is read from the WRANGLR_GITLAB_HOST and GITLAB_HOST
+// environment variables, defaulting to gitlab.com.
+func envTokenHost() string {
+	for _, env := range []string{"WRANGLR_GITLAB_HOST", "GITLAB_HOST"} {
+		if host := os.Getenv(env); host != "" {
+			_, host = cutScheme(host)
+			return strings.TrimSuffix(host, "/")
+		}
+	}
+
+	return "gitlab.com"
+}
+
+type glabConfig struct {
+	Hosts map[string]struct {
+		Token string `yaml:"token"`
+	} `yaml:"hosts"`
+}
+
+func glabToken(host string) string {
+	path := glabConfigPath()
+	if path == "" {
+		return ""
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	config := &glabConfig{}
+	if err := yaml.Unmarshal(raw, config); err != nil {
+		return ""
+	}
+
+	return config.Hosts[host].Token
+}
+
+// glabConfigPath returns the path of the GitLab CLI
+// configuration file, following the same lookup order as glab.
+func glabConfigPath() string {
+	if dir := os.Getenv("GLAB_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "config.yml")
+	}
+
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "glab-cli", "config.yml")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".config", "glab-cli", "config.yml")
+}
@@ -0,0 +1,123 @@
+package gitlab
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+func TestGetAuthToken(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		glab map[string]string
+		env  map[string]string
+		host string
+		want string
+	}{
+		{
+			name: "glab token before environment",
+			glab: map[string]string{"gitlab.com": "glab-gitlab.com"},
+			env:  map[string]string{"GITLAB_TOKEN": "env-token"},
+			host: "gitlab.com",
+			want: "glab-gitlab.com",
+		},
+		{
+			name: "environment token for gitlab.com",
+			env:  map[string]string{"WRANGLR_GITLAB_TOKEN": "wranglr-token", "GITLAB_TOKEN": "env-token"},
+			host: "gitlab.com",
+			want: "wranglr-token",
+		},
+		{
+			name: "GITLAB_TOKEN for gitlab.com",
+			env:  map[string]string{"GITLAB_TOKEN": "env-token"},
+			host: "gitlab.com",
+			want: "env-token",
+		},
+		{
+			name: "glab token for another host",
+			glab: map[string]string{"gitlab.example.com": "glab-gitlab.example.com"},
+			env:  map[string]string{"GITLAB_TOKEN": "env-token"},
+			host: "gitlab.com",
+			want: "env-token",
+		},
+		{
+			name: "environment token not sent to other hosts",
+			env:  map[string]string{"GITLAB_TOKEN": "env-token"},
+			host: "gitlab.example.com",
+		},
+		{
+			name: "environment token for WRANGLR_GITLAB_HOST",
+			env:  map[string]string{"GITLAB_TOKEN": "env-token", "WRANGLR_GITLAB_HOST": "https://gitlab.example.com/"},
+			host: "gitlab.example.com",
+			want: "env-token",
+		},
+		{
+			name: "environment token for GITLAB_HOST",
+			env:  map[string]string{"GITLAB_TOKEN": "env-token", "GITLAB_HOST": "gitlab.example.com"},
+			host: "gitlab.example.com",
+			want: "env-token",
+		},
+		{
+			name: "environment token not sent to gitlab.com when for another host",
+			env:  map[string]string{"GITLAB_TOKEN": "env-token", "WRANGLR_GITLAB_HOST": "gitlab.example.com"},
+			host: "gitlab.com",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setGlabConfig(t, tc.glab)
+
+			for _, env := range []string{"WRANGLR_GITLAB_TOKEN", "GITLAB_TOKEN", "WRANGLR_GITLAB_HOST", "GITLAB_HOST"} {
+				t.Setenv(env, tc.env[env])
+			}
+
+			if got := getAuthToken(tc.host); got != tc.want {
+				t.Errorf("got token %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// setGlabConfig writes a glab configuration with the tokens for each host.
+func setGlabConfig(t *testing.T, tokens map[string]string) {
+	t.Helper()
+
+	config := "hosts:\n"
+	for host, token := range tokens {
+		config += "    " + host + ":\n        token: " + token + "\n"
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GLAB_CONFIG_DIR", dir)
+}
+
+func TestRequestsUseHostToken(t *testing.T) {
+	fake := newFakeGitLab(t)
+	t.Setenv("GITLAB_TOKEN", "gitlab.com-token")
+
+	client := NewClient(fake.URL, fake.Client())
+	if _, err := client.Issues(context.Background(), ListOptions{PerPage: 10}); err != nil {
+		t.Fatalf("listing issues: %v", err)
+	}
+
+	if got := fake.recorded()[0].Header.Get("Authorization"); got != "" {
+		t.Errorf("sent Authorization %q to %s, want the gitlab.com token not to be sent", got, fake.URL)
+	}
+
+	// the fake is served from 127.0.0.1:<port>
+	t.Setenv("WRANGLR_GITLAB_HOST", fake.URL)
+	client = NewClient(fake.URL, httpcache.New(httpcache.Options{Dir: t.TempDir()}).Client())
+	if _, err := client.Issues(context.Background(), ListOptions{PerPage: 10}); err != nil {
+		t.Fatalf("listing issues: %v", err)
+	}
+
+	requests := fake.recorded()
+	if got := requests[len(requests)-1].Header.Get("Authorization"); got != "Bearer gitlab.com-token" {
+		t.Errorf("got Authorization %q, want the token for WRANGLR_GITLAB_HOST", got)
+	}
+}
@@ -0,0 +1,365 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+const (
+	// MaxPerPage is the maximum page size supported by the GitLab API.
+	MaxPerPage = 100
+
+	// enrichConcurrency is the maximum number of merge requests
+	// that are enriched with additional data at the same time.
+	enrichConcurrency = 8
+)
+
+type Client struct {
+	baseURL    string
+	host       string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the GitLab instance at host.
+// The host may optionally include a scheme (i.e "http://gitlab.internal"),
+// otherwise https is used.
+func NewClient(host string, httpClient *http.Client) *Client {
+	scheme, host := cutScheme(host)
+	host = strings.TrimSuffix(host, "/")
+
+	return &Client{
+		baseURL:    fmt.Sprintf("%s://%s/api/v4", scheme, host),
+		host:       host,
+		httpClient: httpClient,
+	}
+}
+
+// cutScheme splits the scheme from a host that may optionally
+// include one, returning https if it doesn't.
+func cutScheme(host string) (scheme, rest string) {
+	if before, after, ok := strings.Cut(host, "://"); ok {
+		return before, after
+	}
+	return "https", host
+}
+
+// ListOptions configures which issues or merge requests are
+// fetched from the GitLab API and how.
+type ListOptions struct {
+	// Project is the ID or full path (i.e "group/project")
+	// of the project to list items from.
+	Project string
+
+	// Group is the ID or full path of the group to list items from.
+	// Ignored if Project is set.
+	Group string
+
+	// State filters items by state (i.e "opened", "closed", "merged", "all").
+	State string
+
+	// Labels filters items to those that have all of the labels.
+	Labels []string
+
+	// Assignee filters items by the username of an assignee.
+	// The special values "none" and "any" filter for
+	// unassigned and assigned items respectively.
+	Assignee string
+
+	// Scope filters items by their relationship to the authenticated
+	// user (i.e "created_by_me", "assigned_to_me", "all").
+	Scope string
+
+	// Limit is the maximum number of items to return.
+	// Values less than or equal to zero return all items.
+	Limit int
+
+	// PerPage is the number of items to request per page.
+	// Values less than or equal to zero, or greater than the
+	// maximum page size, default to the maximum page size.
+	PerPage int
+}
+
+func (lo ListOptions) perPage() int {
+	if lo.PerPage <= 0 || lo.PerPage > MaxPerPage {
+		return MaxPerPage
+	}
+	return lo.PerPage
+}
+
+func (lo ListOptions) done(count int) bool {
+	return lo.Limit > 0 && count >= lo.Limit
+}
+
+func (lo ListOptions) path(resource string) string {
+	switch {
+	case lo.Project != "":
+		return fmt.Sprintf("projects/%s/%s", url.PathEscape(lo.Project), resource)
+	case lo.Group != "":
+		return fmt.Sprintf("groups/%s/%s", url.PathEscape(lo.Group), resource)
+	default:
+		return resource
+	}
+}
+
+func (lo ListOptions) query() url.Values {
+	uv := url.Values{}
+	uv.Set("per_page", strconv.Itoa(lo.perPage()))
+
+	if lo.State != "" {
+		uv.Set("state", lo.State)
+	}
+
+	if len(lo.Labels) > 0 {
+		uv.Set("labels", strings.Join(lo.Labels, ","))
+	}
+
+	switch strings.ToLower(lo.Assignee) {
+	case "":
+	case "none":
+		uv.Set("assignee_id", "None")
+	case "any":
+		uv.Set("assignee_id", "Any")
+	default:
+		uv.Set("assignee_username", lo.Assignee)
+	}
+
+	if lo.Scope != "" {
+		uv.Set("scope", lo.Scope)
+	}
+
+	return uv
+}
+
+// EnrichOptions configures which additional data is
+// fetched for each merge request. Each enrichment
+// requires an additional request per merge request.
+type EnrichOptions struct {
+	// Pipeline fetches the latest pipeline of each merge request.
+	Pipeline bool
+
+	// Approvals fetches the approval state of each merge request.
+	Approvals bool
+}
+
+// ListResult is the result of listing issues or merge requests.
+type ListResult[T any] struct {
+	Items []T
+
+	// Total is the total number of items GitLab reported as matching
+	// the filters, which may be more than the number of items returned.
+	// GitLab doesn't report totals for very large result sets, in which
+	// case this is the number of items that were fetched.
+	Total int
+
+	// Incomplete is true if the results were truncated
+	// due to the configured limit.
+	Incomplete bool
+
+	// StaleSince is the time the oldest of the results was last successfully
+	// fetched from GitLab if any of the results were served from the cache
+	// because GitLab couldn't be reached or wranglr is running offline.
+	// It is the zero time if all results are fresh.
+	StaleSince time.Time
+}
+
+func (c *Client) Issues(ctx context.Context, opts ListOptions) (*ListResult[Issue], error) {
+	staleness := &httpcache.Staleness{}
+
+	out, err := list[Issue](ctx, c, opts.path("issues"), opts, staleness)
+	if err != nil {
+		return nil, fmt.Errorf("fetching gitlab issues: %w", err)
+	}
+
+	out.StaleSince = staleness.Since()
+	return out, nil
+}
+
+func (c *Client) MergeRequests(ctx context.Context, opts ListOptions, enrich EnrichOptions) (*ListResult[MergeRequest], error) {
+	staleness := &httpcache.Staleness{}
+
+	out, err := list[MergeRequest](ctx, c, opts.path("merge_requests"), opts, staleness)
+	if err != nil {
+		return nil, fmt.Errorf("fetching gitlab merge requests: %w", err)
+	}
+
+	if enrich.Pipeline || enrich.Approvals {
+		if err := c.enrich(ctx, enrich, out.Items, staleness); err != nil {
+			return nil, fmt.Errorf("enriching gitlab merge requests: %w", err)
+		}
+	}
+
+	out.StaleSince = staleness.Since()
+	return out, nil
+}
+
+func list[T any](ctx context.Context, c *Client, path string, opts ListOptions, staleness *httpcache.Staleness) (*ListResult[T], error) {
+	out := &ListResult[T]{
+		Items: []T{},
+	}
+
+	uri := fmt.Sprintf("%s/%s?%s", c.baseURL, path, opts.query().Encode())
+	total := -1
+	hasNext := false
+
+	for uri != "" {
+		page := []T{}
+		header, err := c.get(ctx, uri, &page, staleness)
+		if err != nil {
+			return nil, err
+		}
+
+		if total < 0 {
+			if t, err := strconv.Atoi(header.Get("X-Total")); err == nil {
+				total = t
+			}
+		}
+
+		out.Items = append(out.Items, page...)
+
+		uri = nextPage(uri, header)
+		hasNext = uri != ""
+
+		// guard against an infinite loop if GitLab
+		// keeps returning a next page with no items
+		if len(page) == 0 || opts.done(len(out.Items)) {
+			break
+		}
+	}
+
+	if opts.Limit > 0 && len(out.Items) > opts.Limit {
+		out.Items = out.Items[:opts.Limit]
+		hasNext = true
+	}
+
+	out.Total = max(total, len(out.Items))
+	out.Incomplete = hasNext || len(out.Items) < out.Total
+	return out, nil
+}
+
+func (c *Client) enrich(ctx context.Context, enrich EnrichOptions, mrs []MergeRequest, staleness *httpcache.Staleness) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(mrs))
+	sem := make(chan struct{}, enrichConcurrency)
+
+	for i := range mrs {
+		wg.Add(1)
+		go func(mr *MergeRequest) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+
+			if enrich.Pipeline {
+				pipelines := []Pipeline{}
+				uri := fmt.Sprintf("%s/projects/%d/merge_requests/%d/pipelines?per_page=1", c.baseURL, mr.ProjectID, mr.IID)
+				if _, err := c.get(ctx, uri, &pipelines, staleness); err != nil {
+					errs[i] = fmt.Errorf("fetching pipelines for %s: %w", mr.References.Full, err)
+					return
+				}
+				if len(pipelines) > 0 {
+					mr.Pipeline = &pipelines[0]
+				}
+			}
+
+			if enrich.Approvals {
+				approvals := &Approvals{}
+				uri := fmt.Sprintf("%s/projects/%d/merge_requests/%d/approvals", c.baseURL, mr.ProjectID, mr.IID)
+				if _, err := c.get(ctx, uri, approvals, staleness); err != nil {
+					errs[i] = fmt.Errorf("fetching approvals for %s: %w", mr.References.Full, err)
+					return
+				}
+				mr.Approvals = approvals
+			}
+		}(&mrs[i])
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, uri string, into any, staleness *httpcache.Staleness) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Add("Accept", "application/json")
+
+	if token := getAuthToken(c.host); token != "" {
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("doing http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	staleness.Observe(resp)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("request failed with status %q: %s", resp.Status, bodyBytes)
+	}
+
+	err = json.Unmarshal(bodyBytes, into)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshalling results: %w", err)
+	}
+
+	return resp.Header, nil
+}
+
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPage returns the URL of the next page, or an empty string
+// if there is no next page. GitLab returns an RFC 8288 Link header
+// for both offset and keyset pagination, but some proxies strip it
+// so fall back to the X-Next-Page header used by offset pagination.
+func nextPage(uri string, header http.Header) string {
+	if matches := linkNextRegex.FindStringSubmatch(header.Get("Link")); len(matches) == 2 {
+		return matches[1]
+	}
+
+	next := header.Get("X-Next-Page")
+	if next == "" {
+		return ""
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+
+	query := u.Query()
+	query.Set("page", next)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,126 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// fakeGitLab is a GitLab API that serves 5 issues, numbered 1 to 5, and a
+// merge request. Issues are paginated using only the X-Next-Page header,
+// as returned when a proxy strips the Link header.
+type fakeGitLab struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func newFakeGitLab(t *testing.T) *fakeGitLab {
+	t.Helper()
+
+	// only tokens set by the tests are used
+	t.Setenv("GLAB_CONFIG_DIR", t.TempDir())
+	for _, env := range []string{"WRANGLR_GITLAB_TOKEN", "GITLAB_TOKEN", "WRANGLR_GITLAB_HOST", "GITLAB_HOST"} {
+		t.Setenv(env, "")
+	}
+
+	fake := &fakeGitLab{}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, r)
+		fake.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/issues"):
+			fake.issues(w, r)
+		case strings.HasSuffix(r.URL.Path, "/merge_requests"):
+			_, _ = fmt.Fprintf(w, `[{"iid": 7, "title": "Add feature", "web_url": %q, "references": {"full": "group/project!7"}, "draft": true, "source_branch": "feature", "target_branch": "main", "reviewers": [{"username": "reviewer"}]}]`, fake.URL+"/group/project/-/merge_requests/7")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+const fakeIssues = 5
+
+func (f *fakeGitLab) issues(w http.ResponseWriter, r *http.Request) {
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil {
+		page = 1
+	}
+
+	first := (page-1)*perPage + 1
+	last := min(first+perPage-1, fakeIssues)
+	if last < fakeIssues {
+		w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+	}
+	w.Header().Set("X-Total", strconv.Itoa(fakeIssues))
+
+	issues := []string{}
+	for iid := first; iid <= last; iid++ {
+		issues = append(issues, fmt.Sprintf(`{"iid": %d, "title": "Issue %d", "web_url": "%s/group/project/-/issues/%d", "references": {"full": "group/project#%d"}}`, iid, iid, f.URL, iid, iid))
+	}
+	_, _ = fmt.Fprintf(w, "[%s]", strings.Join(issues, ","))
+}
+
+func (f *fakeGitLab) recorded() []*http.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*http.Request{}, f.requests...)
+}
+
+func TestIssuesPagination(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		limit      int
+		want       []int
+		incomplete bool
+		requests   int
+	}{
+		{name: "all pages", want: []int{1, 2, 3, 4, 5}, requests: 3},
+		{name: "limit within a page", limit: 3, want: []int{1, 2, 3}, incomplete: true, requests: 2},
+		{name: "limit at the end of a page", limit: 4, want: []int{1, 2, 3, 4}, incomplete: true, requests: 2},
+		{name: "limit of every issue", limit: 5, want: []int{1, 2, 3, 4, 5}, requests: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newFakeGitLab(t)
+			client := NewClient(fake.URL, httpcache.New(httpcache.Options{Dir: t.TempDir()}).Client())
+
+			result, err := client.Issues(context.Background(), ListOptions{Project: "group/project", PerPage: 2, Limit: tc.limit})
+			if err != nil {
+				t.Fatalf("listing issues: %v", err)
+			}
+
+			got := []int{}
+			for _, issue := range result.Items {
+				got = append(got, issue.IID)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got issues %v, want %v", got, tc.want)
+			}
+			if result.Total != fakeIssues {
+				t.Errorf("got total %d, want %d", result.Total, fakeIssues)
+			}
+			if result.Incomplete != tc.incomplete {
+				t.Errorf("got incomplete %v, want %v", result.Incomplete, tc.incomplete)
+			}
+			if n := len(fake.recorded()); n != tc.requests {
+				t.Errorf("got %d requests, want %d", n, tc.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package gitlab
+
+import (
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+func New(cache *httpcache.Cache) (string, starlark.Value) {
+	return "gitlab", &Module{Cache: cache}
+}
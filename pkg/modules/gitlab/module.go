@@ -0,0 +1,496 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+type Module struct {
+	Cache *httpcache.Cache
+}
+
+func (m *Module) String() string        { return "gitlab" }
+func (m *Module) Type() string          { return "Module" }
+func (m *Module) Truth() starlark.Bool  { return starlark.False }
+func (m *Module) Freeze()               {}
+func (m *Module) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
+
+const (
+	IssuesAttr             = "issues"
+	IssuesAsyncAttr        = "issues_async"
+	MergeRequestsAttr      = "merge_requests"
+	MergeRequestsAsyncAttr = "merge_requests_async"
+)
+
+func (m *Module) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case IssuesAttr:
+		return starlark.NewBuiltin(IssuesAttr, IssuesBuiltin(m.Cache)), nil
+	case IssuesAsyncAttr:
+		return starlark.NewBuiltin(IssuesAsyncAttr, IssuesAsyncBuiltin(m.Cache)), nil
+	case MergeRequestsAttr:
+		return starlark.NewBuiltin(MergeRequestsAttr, MergeRequestsBuiltin(m.Cache)), nil
+	case MergeRequestsAsyncAttr:
+		return starlark.NewBuiltin(MergeRequestsAsyncAttr, MergeRequestsAsyncBuiltin(m.Cache)), nil
+	default:
+		return nil, fmt.Errorf("unknown attribute %q", name)
+	}
+}
+
+func (m *Module) AttrNames() []string {
+	return []string{
+		IssuesAttr,
+		IssuesAsyncAttr,
+		MergeRequestsAttr,
+		MergeRequestsAsyncAttr,
+	}
+}
+
+func IssuesBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.SyncBuiltin(issuesFetch(cache))
+}
+
+func IssuesAsyncBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.AsyncBuiltin(issuesFetch(cache))
+}
+
+func MergeRequestsBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.SyncBuiltin(mergeRequestsFetch(cache))
+}
+
+func MergeRequestsAsyncBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.AsyncBuiltin(mergeRequestsFetch(cache))
+}
+
+const (
+	EnrichPipeline  = "pipeline"
+	EnrichApprovals = "approvals"
+)
+
+// listArgs are the arguments shared by the
+// issues and merge_requests builtins.
+type listArgs struct {
+	host     string
+	group    string
+	opts     ListOptions
+	cacheTTL modules.OptionalDuration
+	enrich   EnrichOptions
+}
+
+func unpackListArgs(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, allowEnrich bool) (*listArgs, error) {
+	var host starlark.String
+	var project starlark.String
+	var gitlabGroup starlark.String
+	var state starlark.String
+	var labels *starlark.List
+	var assignee starlark.String
+	var scope starlark.String
+	var group starlark.String
+	var limit int
+	var perPage int
+	var enrich *starlark.List
+
+	var cacheTTL modules.OptionalDuration
+
+	pairs := []any{
+		"host?", &host,
+		"project?", &project,
+		"gitlab_group?", &gitlabGroup,
+		"state?", &state,
+		"labels?", &labels,
+		"assignee?", &assignee,
+		"scope?", &scope,
+		"group?", &group,
+		"limit?", &limit,
+		"per_page?", &perPage,
+		"cache_ttl?", &cacheTTL,
+	}
+	if allowEnrich {
+		pairs = append(pairs, "enrich?", &enrich)
+	}
+
+	err := starlark.UnpackArgs(fn.Name(), args, kwargs, pairs...)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &listArgs{
+		host:  "gitlab.com",
+		group: group.GoString(),
+		opts: ListOptions{
+			Project:  project.GoString(),
+			Group:    gitlabGroup.GoString(),
+			State:    state.GoString(),
+			Assignee: assignee.GoString(),
+			Scope:    scope.GoString(),
+			Limit:    limit,
+			PerPage:  perPage,
+		},
+		cacheTTL: cacheTTL,
+	}
+
+	if host.GoString() != "" {
+		out.host = host.GoString()
+	}
+
+	out.opts.Labels, err = stringList(fn, "labels", labels)
+	if err != nil {
+		return nil, err
+	}
+
+	enrichments, err := stringList(fn, "enrich", enrich)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, enrichment := range enrichments {
+		switch enrichment {
+		case EnrichPipeline:
+			out.enrich.Pipeline = true
+		case EnrichApprovals:
+			out.enrich.Approvals = true
+		default:
+			return nil, fmt.Errorf("%s: enrich must only contain [%s, %s] but contained %q", fn.Name(), EnrichPipeline, EnrichApprovals, enrichment)
+		}
+	}
+
+	return out, nil
+}
+
+func stringList(fn *starlark.Builtin, name string, list *starlark.List) ([]string, error) {
+	if list == nil {
+		return nil, nil
+	}
+
+	out := []string{}
+	for elem := range list.Elements() {
+		str, ok := starlark.AsString(elem)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s must be a list of strings but contained type %q", fn.Name(), name, elem.Type())
+		}
+		out = append(out, str)
+	}
+
+	return out, nil
+}
+
+func issuesFetch(cache *httpcache.Cache) modules.FetchBuilder {
+	return func(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (modules.FetchFunc, error) {
+		la, err := unpackListArgs(fn, args, kwargs, false)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context) (starlark.Value, error) {
+			if la.cacheTTL.Set {
+				ctx = httpcache.WithTTL(ctx, la.cacheTTL.Duration)
+			}
+
+			client := NewClient(la.host, cache.Client())
+
+			results, err := client.Issues(ctx, la.opts)
+			if err != nil {
+				return nil, err
+			}
+
+			elems := []starlark.Value{}
+			for _, issue := range results.Items {
+				elems = append(elems, &Item{
+					BaseItem: modules.NewBaseItem(la.group, results.StaleSince),
+					resource: issue.Resource,
+					raw:      issue,
+				})
+			}
+
+			return modules.NewResults(elems, results.Total, results.Incomplete), nil
+		}, nil
+	}
+}
+
+func mergeRequestsFetch(cache *httpcache.Cache) modules.FetchBuilder {
+	return func(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (modules.FetchFunc, error) {
+		la, err := unpackListArgs(fn, args, kwargs, true)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context) (starlark.Value, error) {
+			if la.cacheTTL.Set {
+				ctx = httpcache.WithTTL(ctx, la.cacheTTL.Duration)
+			}
+
+			client := NewClient(la.host, cache.Client())
+
+			results, err := client.MergeRequests(ctx, la.opts, la.enrich)
+			if err != nil {
+				return nil, err
+			}
+
+			elems := []starlark.Value{}
+			for _, mr := range results.Items {
+				elems = append(elems, &Item{
+					BaseItem:     modules.NewBaseItem(la.group, results.StaleSince),
+					resource:     mr.Resource,
+					mergeRequest: &mr,
+					raw:          mr,
+				})
+			}
+
+			return modules.NewResults(elems, results.Total, results.Incomplete), nil
+		}, nil
+	}
+}
+
+type ItemType string
+
+const (
+	ItemTypeIssue        ItemType = "issue"
+	ItemTypeMergeRequest ItemType = "mergerequest"
+)
+
+// Item is a GitLab issue or merge request.
+type Item struct {
+	modules.BaseItem
+	resource Resource
+
+	// mergeRequest is nil if the item is an issue.
+	mergeRequest *MergeRequest
+
+	raw any
+}
+
+var _ modules.Item = (*Item)(nil)
+
+func (i *Item) URL() string {
+	return i.resource.WebURL
+}
+
+func (i *Item) Source() string {
+	return "gitlab"
+}
+
+// ID returns an identifier for the item of the form "group/project#iid"
+// for issues and "group/project!iid" for merge requests.
+func (i *Item) ID() string {
+	return i.resource.References.Full
+}
+
+func (i *Item) Title() string {
+	return i.resource.Title
+}
+
+func (i *Item) Body() string {
+	return i.resource.Description
+}
+
+func (i *Item) Author() string {
+	return i.resource.Author.Username
+}
+
+func (i *Item) Assignees() []string {
+	return usernames(i.resource.Assignees)
+}
+
+func (i *Item) Labels() []string {
+	return append([]string{}, i.resource.Labels...)
+}
+
+func (i *Item) CreatedAt() time.Time {
+	return i.resource.CreatedAt
+}
+
+func (i *Item) UpdatedAt() time.Time {
+	return i.resource.UpdatedAt
+}
+
+// Project returns the full path of the
+// project the item belongs to.
+func (i *Item) Project() string {
+	return i.resource.References.Project()
+}
+
+// Resource returns the fields shared by issues and merge requests.
+func (i *Item) Resource() Resource {
+	return i.resource
+}
+
+// MergeRequest returns the merge request, or
+// nil if the item is an issue.
+func (i *Item) MergeRequest() *MergeRequest {
+	return i.mergeRequest
+}
+
+// Raw returns the item as returned by the GitLab API.
+func (i *Item) Raw() any {
+	return i.raw
+}
+
+func (i *Item) String() string { return i.Source() + " " + i.ID() }
+func (i *Item) Type() string {
+	if i.mergeRequest != nil {
+		return string(ItemTypeMergeRequest)
+	}
+
+	return string(ItemTypeIssue)
+}
+func (i *Item) Truth() starlark.Bool  { return starlark.True }
+func (i *Item) Freeze()               {}
+func (i *Item) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
+
+// mergeRequestAttrs are the attributes that are only
+// meaningful for merge requests. They are None for issues.
+var mergeRequestAttrs = []string{
+	"draft",
+	"source_branch",
+	"target_branch",
+	"merge_status",
+	"merged_at",
+	"reviewers",
+	"pipeline_status",
+	"pipeline_url",
+	"approved",
+	"approvals_required",
+	"approvals_left",
+	"approved_by",
+}
+
+func (i *Item) Attr(name string) (starlark.Value, error) {
+	if val, err := i.BaseItem.Attr(name); val != nil || err != nil {
+		return val, err
+	}
+
+	if i.mergeRequest == nil && slices.Contains(mergeRequestAttrs, name) {
+		return starlark.None, nil
+	}
+
+	switch name {
+	case "assignees":
+		return modules.StringList(i.Assignees()), nil
+	case "author":
+		return starlark.String(i.Author()), nil
+	case "body":
+		return starlark.String(i.resource.Description), nil
+	case "closed_at":
+		return modules.OptionalTime(i.resource.ClosedAt), nil
+	case "comments":
+		return starlark.MakeInt(i.resource.UserNotesCount), nil
+	case "created_at":
+		return modules.Time(i.resource.CreatedAt), nil
+	case "labels":
+		return modules.StringList(i.Labels()), nil
+	case "milestone":
+		if i.resource.Milestone == nil {
+			return starlark.None, nil
+		}
+		return starlark.String(i.resource.Milestone.Title), nil
+	case "number":
+		return starlark.MakeInt(i.resource.IID), nil
+	case "project":
+		return starlark.String(i.Project()), nil
+	case "reference":
+		return starlark.String(i.resource.References.Full), nil
+	case "state":
+		return starlark.String(i.resource.State), nil
+	case "title":
+		return starlark.String(i.resource.Title), nil
+	case "updated_at":
+		return modules.Time(i.resource.UpdatedAt), nil
+	case modules.AgeAttr:
+		return modules.Since(i.CreatedAt()), nil
+	case modules.SinceUpdateAttr:
+		return modules.Since(i.UpdatedAt()), nil
+	case "url":
+		return starlark.String(i.resource.WebURL), nil
+	case "draft":
+		return starlark.Bool(i.mergeRequest.Draft), nil
+	case "source_branch":
+		return starlark.String(i.mergeRequest.SourceBranch), nil
+	case "target_branch":
+		return starlark.String(i.mergeRequest.TargetBranch), nil
+	case "merge_status":
+		return starlark.String(i.mergeRequest.DetailedMergeStatus), nil
+	case "merged_at":
+		return modules.OptionalTime(i.mergeRequest.MergedAt), nil
+	case "reviewers":
+		return modules.StringList(usernames(i.mergeRequest.Reviewers)), nil
+	case "pipeline_status":
+		if i.mergeRequest.Pipeline == nil {
+			return starlark.None, nil
+		}
+		return starlark.String(i.mergeRequest.Pipeline.Status), nil
+	case "pipeline_url":
+		if i.mergeRequest.Pipeline == nil {
+			return starlark.None, nil
+		}
+		return starlark.String(i.mergeRequest.Pipeline.WebURL), nil
+	case "approved":
+		if i.mergeRequest.Approvals == nil {
+			return starlark.None, nil
+		}
+		return starlark.Bool(i.mergeRequest.Approvals.Approved), nil
+	case "approvals_required":
+		if i.mergeRequest.Approvals == nil {
+			return starlark.None, nil
+		}
+		return starlark.MakeInt(i.mergeRequest.Approvals.ApprovalsRequired), nil
+	case "approvals_left":
+		if i.mergeRequest.Approvals == nil {
+			return starlark.None, nil
+		}
+		return starlark.MakeInt(i.mergeRequest.Approvals.ApprovalsLeft), nil
+	case "approved_by":
+		if i.mergeRequest.Approvals == nil {
+			return starlark.None, nil
+		}
+		approvers := []User{}
+		for _, approver := range i.mergeRequest.Approvals.ApprovedBy {
+			approvers = append(approvers, approver.User)
+		}
+		return modules.StringList(usernames(approvers)), nil
+	default:
+		return nil, fmt.Errorf("unknown attribute %q", name)
+	}
+}
+
+func (i *Item) AttrNames() []string {
+	names := append(i.BaseItem.AttrNames(),
+		"assignees",
+		"author",
+		"body",
+		"closed_at",
+		"comments",
+		"created_at",
+		"labels",
+		"milestone",
+		"number",
+		"project",
+		"reference",
+		"state",
+		"title",
+		"updated_at",
+		"url",
+		modules.AgeAttr,
+		modules.SinceUpdateAttr,
+	)
+	return append(names, mergeRequestAttrs...)
+}
+
+// SetField sets the wranglr-specific field or custom field with the provided name.
+func (i *Item) SetField(name string, val starlark.Value) error {
+	return i.BaseItem.SetItemField(i, name, val)
+}
+
+func usernames(users []User) []string {
+	out := []string{}
+	for _, user := range users {
+		out = append(out, user.Username)
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+	"github.com/everettraven/wranglr/pkg/modules"
+	"go.starlark.net/starlark"
+)
+
+// fetchItem calls the builtin with the provided name against
+// the fake and returns the first item it returns.
+func fetchItem(t *testing.T, fake *fakeGitLab, name string) *Item {
+	t.Helper()
+
+	module := &Module{Cache: httpcache.New(httpcache.Options{Dir: t.TempDir()})}
+	builtin, err := module.Attr(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kwargs := []starlark.Tuple{
+		{starlark.String("host"), starlark.String(fake.URL)},
+		{starlark.String("project"), starlark.String("group/project")},
+		{starlark.String("limit"), starlark.MakeInt(1)},
+	}
+	val, err := starlark.Call(&starlark.Thread{Name: "test"}, builtin, nil, kwargs)
+	if err != nil {
+		t.Fatalf("calling %s: %v", name, err)
+	}
+
+	results := val.(*modules.Results)
+	if results.Len() == 0 {
+		t.Fatalf("%s returned no items", name)
+	}
+	return results.Index(0).(*Item)
+}
+
+func TestMergeRequestAttrsOnIssues(t *testing.T) {
+	fake := newFakeGitLab(t)
+	item := fetchItem(t, fake, IssuesAttr)
+
+	for _, name := range mergeRequestAttrs {
+		val, err := item.Attr(name)
+		if err != nil {
+			t.Fatalf("getting %s: %v", name, err)
+		}
+		if val != starlark.None {
+			t.Errorf("got %s %v for an issue, want None", name, val)
+		}
+	}
+
+	if got, want := item.String(), "gitlab group/project#1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !item.Truth() {
+		t.Error("expected the issue to be truthy")
+	}
+}
+
+func TestMergeRequestAttrs(t *testing.T) {
+	fake := newFakeGitLab(t)
+	item := fetchItem(t, fake, MergeRequestsAttr)
+
+	for name, want := range map[string]starlark.Value{
+		"draft":         starlark.True,
+		"source_branch": starlark.String("feature"),
+		"target_branch": starlark.String("main"),
+	} {
+		got, err := item.Attr(name)
+		if err != nil {
+			t.Fatalf("getting %s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("got %s %v, want %v", name, got, want)
+		}
+	}
+
+	reviewers, err := item.Attr("reviewers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reviewers.String(); got != `["reviewer"]` {
+		t.Errorf("got reviewers %s, want [\"reviewer\"]", got)
+	}
+}
@@ -0,0 +1,98 @@
+package gitlab
+
+import (
+	"strings"
+	"time"
+)
+
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	WebURL   string `json:"web_url"`
+}
+
+type References struct {
+	Short    string `json:"short"`
+	Relative string `json:"relative"`
+	Full     string `json:"full"`
+}
+
+// Project returns the full path of the project
+// the referenced item belongs to.
+func (r References) Project() string {
+	if i := strings.LastIndexAny(r.Full, "#!"); i >= 0 {
+		return r.Full[:i]
+	}
+	return r.Full
+}
+
+type Milestone struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+// Resource is the set of fields shared
+// by GitLab issues and merge requests.
+type Resource struct {
+	ID             int        `json:"id"`
+	IID            int        `json:"iid"`
+	ProjectID      int        `json:"project_id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	State          string     `json:"state"`
+	WebURL         string     `json:"web_url"`
+	Author         User       `json:"author"`
+	Assignees      []User     `json:"assignees"`
+	Labels         []string   `json:"labels"`
+	Milestone      *Milestone `json:"milestone"`
+	UserNotesCount int        `json:"user_notes_count"`
+	References     References `json:"references"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ClosedAt       *time.Time `json:"closed_at"`
+}
+
+type Issue struct {
+	Resource
+	Confidential bool   `json:"confidential"`
+	DueDate      string `json:"due_date"`
+	IssueType    string `json:"issue_type"`
+}
+
+type MergeRequest struct {
+	Resource
+	Draft               bool       `json:"draft"`
+	SourceBranch        string     `json:"source_branch"`
+	TargetBranch        string     `json:"target_branch"`
+	DetailedMergeStatus string     `json:"detailed_merge_status"`
+	MergedAt            *time.Time `json:"merged_at"`
+	Reviewers           []User     `json:"reviewers"`
+
+	// Pipeline is the latest pipeline of the merge request.
+	// It is only populated when the pipeline enrichment is requested.
+	Pipeline *Pipeline `json:"pipeline,omitempty"`
+
+	// Approvals is the approval state of the merge request.
+	// It is only populated when the approvals enrichment is requested.
+	Approvals *Approvals `json:"approvals,omitempty"`
+}
+
+type Pipeline struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	Ref    string `json:"ref"`
+	SHA    string `json:"sha"`
+	WebURL string `json:"web_url"`
+}
+
+type Approver struct {
+	User User `json:"user"`
+}
+
+type Approvals struct {
+	Approved          bool       `json:"approved"`
+	ApprovalsRequired int        `json:"approvals_required"`
+	ApprovalsLeft     int        `json:"approvals_left"`
+	ApprovedBy        []Approver `json:"approved_by"`
+}
@@ -0,0 +1,315 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	starjson "go.starlark.net/lib/json"
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// maxPages is the maximum number of pages that are fetched when
+// paginating, which guards against APIs that never stop returning
+// a next page.
+const maxPages = 1000
+
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient(httpClient *http.Client) *Client {
+	return &Client{httpClient: httpClient}
+}
+
+// Request is a request for a JSON document.
+type Request struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Pagination configures how the next page of results is found. At most one
+// of its fields is set, and the zero value doesn't paginate.
+type Pagination struct {
+	// Link follows the URL of the "next" relation of the Link header.
+	Link bool
+
+	// Next is the path of the URL of the next page within each page.
+	// Relative URLs are resolved against the URL of the page.
+	Next *Path
+
+	// Cursor is the path of the cursor for the next page within each page,
+	// which is passed to the next request using the CursorParam query parameter.
+	Cursor      *Path
+	CursorParam string
+}
+
+func (p Pagination) enabled() bool {
+	return p.Link || p.Next != nil || p.Cursor != nil
+}
+
+// FetchOptions configures which values are returned by Fetch.
+type FetchOptions struct {
+	// Records is the path of the list of records within each page.
+	// If it isn't set, the whole of each page is the list of records.
+	Records *Path
+
+	Pagination Pagination
+
+	// Limit is the maximum number of records to return when
+	// Records is set or paginating. Values less than or equal
+	// to zero return all records.
+	Limit int
+}
+
+// FetchResult is the result of fetching a JSON document.
+type FetchResult struct {
+	// Value is the decoded document. It is only set
+	// if Records isn't set and pagination is disabled.
+	Value starlark.Value
+
+	// Records are the records from every page.
+	Records []starlark.Value
+
+	// Incomplete is true if the records were
+	// truncated due to the configured limit.
+	Incomplete bool
+
+	// StaleSince is the time the oldest of the pages was last successfully
+	// fetched if any of them were served from the cache because the server
+	// couldn't be reached or wranglr is running offline. It is the zero time
+	// if all pages are fresh.
+	StaleSince time.Time
+}
+
+// Fetch fetches the JSON document for the request, following pagination
+// to collect the records of every page if configured to.
+func (c *Client) Fetch(ctx context.Context, req Request, opts FetchOptions) (*FetchResult, error) {
+	staleness := &httpcache.Staleness{}
+
+	if opts.Records == nil && !opts.Pagination.enabled() {
+		value, _, err := c.do(ctx, req, staleness)
+		if err != nil {
+			return nil, err
+		}
+
+		return &FetchResult{Value: value, StaleSince: staleness.Since()}, nil
+	}
+
+	out := &FetchResult{Records: []starlark.Value{}}
+	visited := map[string]bool{}
+	for page := 0; req.URL != ""; page++ {
+		if page == maxPages {
+			return nil, fmt.Errorf("stopped paginating after %d pages", maxPages)
+		}
+		visited[req.URL] = true
+
+		value, header, err := c.do(ctx, req, staleness)
+		if err != nil {
+			return nil, err
+		}
+
+		records, err := recordsOf(value, opts.Records)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", req.URL, err)
+		}
+		out.Records = append(out.Records, records...)
+
+		next, err := nextPage(req.URL, value, header, opts.Pagination)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", req.URL, err)
+		}
+
+		// guard against an infinite loop if the API keeps
+		// returning the same page or pages without records
+		if visited[next] || len(records) == 0 {
+			next = ""
+		}
+
+		if opts.Limit > 0 && len(out.Records) >= opts.Limit {
+			out.Incomplete = next != "" || len(out.Records) > opts.Limit
+			out.Records = out.Records[:opts.Limit]
+			break
+		}
+
+		req.URL = next
+	}
+
+	out.StaleSince = staleness.Since()
+	return out, nil
+}
+
+// recordsOf returns the elements of the list of records at path within the page.
+func recordsOf(page starlark.Value, path *Path) ([]starlark.Value, error) {
+	value := page
+	if path != nil {
+		found, ok := path.Get(page)
+		if !ok {
+			return nil, fmt.Errorf("records %q not found", path)
+		}
+		value = found
+	}
+
+	// an empty result set is often returned as null
+	if value == starlark.None {
+		return nil, nil
+	}
+
+	list, ok := value.(*starlark.List)
+	if !ok {
+		return nil, fmt.Errorf("records must be a list but was type %q", value.Type())
+	}
+
+	records := []starlark.Value{}
+	for elem := range list.Elements() {
+		records = append(records, elem)
+	}
+	return records, nil
+}
+
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPage returns the URL of the page after the page at uri, or
+// an empty string if it is the last page or pagination is disabled.
+func nextPage(uri string, page starlark.Value, header http.Header, pagination Pagination) (string, error) {
+	var next string
+	switch {
+	case pagination.Link:
+		matches := linkNextRegex.FindStringSubmatch(header.Get("Link"))
+		if len(matches) != 2 {
+			return "", nil
+		}
+		next = matches[1]
+	case pagination.Next != nil:
+		value, ok := pagination.Next.Get(page)
+		if !ok || value == starlark.None {
+			return "", nil
+		}
+
+		str, ok := starlark.AsString(value)
+		if !ok {
+			return "", fmt.Errorf("next page URL %q must be a string but was type %q", pagination.Next, value.Type())
+		}
+		next = str
+	case pagination.Cursor != nil:
+		value, ok := pagination.Cursor.Get(page)
+		if !ok || value == starlark.None || value == starlark.False {
+			return "", nil
+		}
+
+		// numeric cursors are passed as-is
+		cursor, ok := starlark.AsString(value)
+		if !ok {
+			cursor = value.String()
+		}
+		if cursor == "" {
+			return "", nil
+		}
+
+		u, err := url.Parse(uri)
+		if err != nil {
+			return "", err
+		}
+		query := u.Query()
+		query.Set(pagination.CursorParam, cursor)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	default:
+		return "", nil
+	}
+
+	if next == "" {
+		return "", nil
+	}
+
+	base, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("parsing next page URL: %w", err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// do sends the request and decodes the JSON response.
+func (c *Client) do(ctx context.Context, r Request, staleness *httpcache.Staleness) (starlark.Value, http.Header, error) {
+	var body io.Reader
+	if r.Body != nil {
+		body = bytes.NewReader(r.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("building request: %w", err)
+	}
+
+	for key, values := range r.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "application/json")
+	}
+	if r.Body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("doing http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	staleness.Observe(resp)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("request to %s failed with status %q: %s", req.URL.Redacted(), resp.Status, bodyBytes)
+	}
+
+	value, err := decode(bodyBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding response from %s: %w", req.URL.Redacted(), err)
+	}
+
+	return value, resp.Header, nil
+}
+
+// decode decodes a JSON document into Starlark values the same way as
+// json.decode(...), which keeps the order of the keys of objects.
+func decode(data []byte) (starlark.Value, error) {
+	// the response is decoded outside of the Starlark thread that
+	// requested it, so the builtin is called on a thread of its own
+	thread := &starlark.Thread{Name: "decode"}
+	return starlark.Call(thread, starjson.Module.Members["decode"], starlark.Tuple{starlark.String(data)}, nil)
+}
+
+// encode encodes a Starlark value as a JSON document
+// the same way as json.encode(...).
+func encode(value starlark.Value) ([]byte, error) {
+	thread := &starlark.Thread{Name: "encode"}
+	encoded, err := starlark.Call(thread, starjson.Module.Members["encode"], starlark.Tuple{value}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	str, _ := starlark.AsString(encoded)
+	return []byte(str), nil
+}
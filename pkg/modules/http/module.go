@@ -0,0 +1,555 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+type Module struct {
+	Cache *httpcache.Cache
+}
+
+func (m *Module) String() string        { return "http" }
+func (m *Module) Type() string          { return "Module" }
+func (m *Module) Truth() starlark.Bool  { return starlark.False }
+func (m *Module) Freeze()               {}
+func (m *Module) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
+
+const (
+	FetchAttr      = "fetch"
+	FetchAsyncAttr = "fetch_async"
+	ItemsAttr      = "items"
+)
+
+const (
+	PaginateLink   = "link"
+	PaginateNext   = "next"
+	PaginateCursor = "cursor"
+)
+
+func (m *Module) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case FetchAttr:
+		return starlark.NewBuiltin(FetchAttr, FetchBuiltin(m.Cache)), nil
+	case FetchAsyncAttr:
+		return starlark.NewBuiltin(FetchAsyncAttr, FetchAsyncBuiltin(m.Cache)), nil
+	case ItemsAttr:
+		return starlark.NewBuiltin(ItemsAttr, ItemsBuiltin()), nil
+	default:
+		return nil, fmt.Errorf("unknown attribute %q", name)
+	}
+}
+
+func (m *Module) AttrNames() []string {
+	return []string{
+		FetchAttr,
+		FetchAsyncAttr,
+		ItemsAttr,
+	}
+}
+
+func FetchBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.SyncBuiltin(fetch(cache))
+}
+
+func FetchAsyncBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.AsyncBuiltin(fetch(cache))
+}
+
+func fetch(cache *httpcache.Cache) modules.FetchBuilder {
+	return func(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (modules.FetchFunc, error) {
+		var uri starlark.String
+		var method starlark.String
+		var headers *starlark.Dict
+		var body starlark.Value
+		var tokenEnv starlark.String
+		var authHeader starlark.String = "Authorization"
+		var authScheme starlark.String = "Bearer"
+		var records Path
+		var paginate starlark.Value
+		var limit int
+		var caBundle starlark.String
+
+		var cacheTTL modules.OptionalDuration
+
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+			"url", &uri,
+			"method?", &method,
+			"headers?", &headers,
+			"body?", &body,
+			"token_env?", &tokenEnv,
+			"auth_header?", &authHeader,
+			"auth_scheme?", &authScheme,
+			"records?", &records,
+			"paginate?", &paginate,
+			"limit?", &limit,
+			"cache_ttl?", &cacheTTL,
+			"ca_bundle?", &caBundle,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		req := Request{
+			Method: strings.ToUpper(method.GoString()),
+			URL:    uri.GoString(),
+			Header: http.Header{},
+		}
+
+		switch req.Method {
+		case "":
+			req.Method = http.MethodGet
+		case http.MethodGet, http.MethodPost:
+		default:
+			return nil, fmt.Errorf("%s: method must be one of [%s, %s] but was %q", fn.Name(), http.MethodGet, http.MethodPost, method.GoString())
+		}
+
+		if headers != nil {
+			for key, value := range headers.Entries() {
+				name, ok := starlark.AsString(key)
+				if !ok {
+					return nil, fmt.Errorf("%s: headers must have string keys but contained a key of type %q", fn.Name(), key.Type())
+				}
+				str, ok := starlark.AsString(value)
+				if !ok {
+					return nil, fmt.Errorf("%s: header %q must be a string but was type %q", fn.Name(), name, value.Type())
+				}
+				req.Header.Add(name, str)
+			}
+		}
+
+		switch b := body.(type) {
+		case nil, starlark.NoneType:
+		case starlark.String:
+			req.Body = []byte(b)
+		default:
+			req.Body, err = encode(body)
+			if err != nil {
+				return nil, fmt.Errorf("%s: encoding body: %w", fn.Name(), err)
+			}
+		}
+
+		if tokenEnv.GoString() != "" {
+			token := os.Getenv(tokenEnv.GoString())
+			if token == "" {
+				return nil, fmt.Errorf("%s: environment variable %q is not set", fn.Name(), tokenEnv.GoString())
+			}
+
+			if authScheme.GoString() != "" {
+				token = authScheme.GoString() + " " + token
+			}
+			req.Header.Set(authHeader.GoString(), token)
+		}
+
+		opts := FetchOptions{Limit: limit}
+		if records.raw != "" {
+			opts.Records = &records
+		}
+
+		opts.Pagination, err = unpackPagination(paginate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: paginate %w", fn.Name(), err)
+		}
+
+		transport, err := modules.CABundleTransport(caBundle.GoString())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", fn.Name(), err)
+		}
+		client := NewClient(&http.Client{Transport: cache.Transport(transport)})
+
+		return func(ctx context.Context) (starlark.Value, error) {
+			if cacheTTL.Set {
+				ctx = httpcache.WithTTL(ctx, cacheTTL.Duration)
+			}
+
+			// fetching only reads data, so POST requests (i.e
+			// search queries) are cached like GET requests
+			if req.Method == http.MethodPost {
+				ctx = httpcache.WithIdempotent(ctx)
+			}
+
+			result, err := client.Fetch(ctx, req, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			if result.Value != nil {
+				return result.Value, nil
+			}
+
+			results := modules.NewResults(result.Records, len(result.Records), result.Incomplete)
+			results.SetStaleSince(result.StaleSince)
+			return results, nil
+		}, nil
+	}
+}
+
+// unpackPagination unpacks the paginate argument, which is either "link"
+// or a dict of the form {"next": path} or {"cursor": path, "param": name}.
+func unpackPagination(value starlark.Value) (Pagination, error) {
+	switch v := value.(type) {
+	case nil, starlark.NoneType:
+		return Pagination{}, nil
+	case starlark.String:
+		if v != PaginateLink {
+			return Pagination{}, fmt.Errorf("must be %q or a dict but was %q", PaginateLink, v.GoString())
+		}
+		return Pagination{Link: true}, nil
+	case *starlark.Dict:
+		pagination := Pagination{CursorParam: PaginateCursor}
+		for key, elem := range v.Entries() {
+			name, _ := starlark.AsString(key)
+			str, ok := starlark.AsString(elem)
+			if !ok {
+				return Pagination{}, fmt.Errorf("%s must be a string but was type %q", key, elem.Type())
+			}
+
+			switch name {
+			case PaginateNext, PaginateCursor:
+				path, err := ParsePath(str)
+				if err != nil {
+					return Pagination{}, err
+				}
+				if name == PaginateNext {
+					pagination.Next = path
+				} else {
+					pagination.Cursor = path
+				}
+			case "param":
+				pagination.CursorParam = str
+			default:
+				return Pagination{}, fmt.Errorf("must only contain the keys [%s, %s, param] but contained %s", PaginateNext, PaginateCursor, key)
+			}
+		}
+
+		if (pagination.Next == nil) == (pagination.Cursor == nil) {
+			return Pagination{}, fmt.Errorf("must contain exactly one of the keys [%s, %s]", PaginateNext, PaginateCursor)
+		}
+		return pagination, nil
+	default:
+		return Pagination{}, fmt.Errorf("must be %q or a dict but was type %q", PaginateLink, value.Type())
+	}
+}
+
+// ItemsBuiltin returns a builtin that maps records, such as those returned
+// by http.fetch(...), to items using the paths of their fields.
+func ItemsBuiltin() modules.BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var records starlark.Value
+		var m mapping
+		var group starlark.String
+		var fields *starlark.Dict
+
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+			"records", &records,
+			"title", &m.title,
+			"url?", &m.url,
+			"id?", &m.id,
+			"body?", &m.body,
+			"author?", &m.author,
+			"assignees?", &m.assignees,
+			"labels?", &m.labels,
+			"created_at?", &m.createdAt,
+			"updated_at?", &m.updatedAt,
+			"group?", &group,
+			"fields?", &fields,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if fields != nil {
+			m.fields = map[string]*Path{}
+			for key, value := range fields.Entries() {
+				name, ok := starlark.AsString(key)
+				if !ok {
+					return nil, fmt.Errorf("%s: fields must have string keys but contained a key of type %q", fn.Name(), key.Type())
+				}
+
+				path := &Path{}
+				if err := path.Unpack(value); err != nil {
+					return nil, fmt.Errorf("%s: field %q: %w", fn.Name(), name, err)
+				}
+				m.fields[name] = path
+			}
+		}
+
+		var list starlark.Indexable
+		var staleSince time.Time
+		totalCount, incomplete := 0, false
+		switch v := records.(type) {
+		case *modules.Results:
+			list = v.List
+			staleSince = v.StaleSince()
+			totalCount, incomplete = v.TotalCount(), v.Incomplete()
+		case *starlark.List:
+			list = v
+		case starlark.Tuple:
+			list = v
+		default:
+			return nil, fmt.Errorf("%s: records must be a list, but was type %s", fn.Name(), records.Type())
+		}
+
+		elems := []starlark.Value{}
+		for i := range list.Len() {
+			item, err := m.item(list.Index(i), group.GoString(), staleSince)
+			if err != nil {
+				return nil, fmt.Errorf("%s: record %d: %w", fn.Name(), i, err)
+			}
+
+			modules.SetState(thread, item)
+			elems = append(elems, item)
+		}
+
+		return modules.NewResults(elems, max(totalCount, len(elems)), incomplete), nil
+	}
+}
+
+// mapping maps records to items using the paths of their fields.
+type mapping struct {
+	title     Path
+	url       Path
+	id        Path
+	body      Path
+	author    Path
+	assignees Path
+	labels    Path
+	createdAt Path
+	updatedAt Path
+
+	// fields are the paths of the custom fields, by name.
+	fields map[string]*Path
+}
+
+func (m *mapping) item(record starlark.Value, group string, staleSince time.Time) (*Item, error) {
+	item := &Item{
+		BaseItem:  modules.NewBaseItem(group, staleSince),
+		record:    record,
+		id:        asString(m.id.lookup(record)),
+		title:     asString(m.title.lookup(record)),
+		url:       asString(m.url.lookup(record)),
+		body:      asString(m.body.lookup(record)),
+		author:    asString(m.author.lookup(record)),
+		assignees: asStrings(m.assignees.lookup(record)),
+		labels:    asStrings(m.labels.lookup(record)),
+	}
+
+	var err error
+	item.createdAt, err = asTime(m.createdAt.lookup(record))
+	if err != nil {
+		return nil, fmt.Errorf("created_at: %w", err)
+	}
+
+	item.updatedAt, err = asTime(m.updatedAt.lookup(record))
+	if err != nil {
+		return nil, fmt.Errorf("updated_at: %w", err)
+	}
+
+	for name, path := range m.fields {
+		if err := item.SetField(name, path.lookup(record)); err != nil {
+			return nil, err
+		}
+	}
+
+	return item, nil
+}
+
+func asString(value starlark.Value) string {
+	if value == starlark.None {
+		return ""
+	}
+
+	// values that aren't strings, such as numeric IDs, are formatted
+	if str, ok := starlark.AsString(value); ok {
+		return str
+	}
+	return value.String()
+}
+
+// asStrings returns the elements of a list as strings, or a single
+// value as a list containing it.
+func asStrings(value starlark.Value) []string {
+	strs := []string{}
+	switch v := value.(type) {
+	case starlark.NoneType:
+	case starlark.String:
+		// strings are indexable too, but are a single value
+		strs = append(strs, string(v))
+	case starlark.Indexable:
+		for i := range v.Len() {
+			if elem := v.Index(i); elem != starlark.None {
+				strs = append(strs, asString(elem))
+			}
+		}
+	default:
+		strs = append(strs, asString(value))
+	}
+	return strs
+}
+
+// asTime parses a timestamp, which is either an
+// RFC 3339 string or the number of seconds since the epoch.
+func asTime(value starlark.Value) (time.Time, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return time.Time{}, nil
+	case starlark.String:
+		if v == "" {
+			return time.Time{}, nil
+		}
+		return time.Parse(time.RFC3339, string(v))
+	case starlark.Int:
+		seconds, ok := v.Int64()
+		if !ok {
+			return time.Time{}, fmt.Errorf("timestamp %s is out of range", v)
+		}
+		return time.Unix(seconds, 0), nil
+	case starlark.Float:
+		return time.UnixMilli(int64(float64(v) * 1000)), nil
+	default:
+		return time.Time{}, fmt.Errorf("must be an RFC 3339 string or a number of seconds but was type %q", value.Type())
+	}
+}
+
+// Item is an item mapped from a record returned by a JSON API.
+type Item struct {
+	modules.BaseItem
+	record starlark.Value
+
+	id        string
+	title     string
+	url       string
+	body      string
+	author    string
+	assignees []string
+	labels    []string
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+var _ modules.Item = (*Item)(nil)
+
+func (i *Item) URL() string {
+	return i.url
+}
+
+func (i *Item) Source() string {
+	return "http"
+}
+
+// ID returns the mapped ID of the item, falling back to its URL or title.
+func (i *Item) ID() string {
+	switch {
+	case i.id != "":
+		return i.id
+	case i.url != "":
+		return i.url
+	default:
+		return i.title
+	}
+}
+
+func (i *Item) Title() string {
+	return i.title
+}
+
+func (i *Item) Body() string {
+	return i.body
+}
+
+func (i *Item) Author() string {
+	return i.author
+}
+
+func (i *Item) Assignees() []string {
+	return append([]string{}, i.assignees...)
+}
+
+func (i *Item) Labels() []string {
+	return append([]string{}, i.labels...)
+}
+
+func (i *Item) CreatedAt() time.Time {
+	return i.createdAt
+}
+
+func (i *Item) UpdatedAt() time.Time {
+	return i.updatedAt
+}
+
+// Raw returns the record the item was mapped from.
+func (i *Item) Raw() any {
+	return modules.JSONValue(i.record)
+}
+
+func (i *Item) String() string        { return i.Source() + " " + i.ID() }
+func (i *Item) Type() string          { return fmt.Sprintf("%T", i) }
+func (i *Item) Truth() starlark.Bool  { return starlark.True }
+func (i *Item) Freeze()               {}
+func (i *Item) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
+
+func (i *Item) Attr(name string) (starlark.Value, error) {
+	if val, err := i.BaseItem.Attr(name); val != nil || err != nil {
+		return val, err
+	}
+
+	switch name {
+	case "id":
+		return starlark.String(i.ID()), nil
+	case "title":
+		return starlark.String(i.title), nil
+	case "url":
+		return starlark.String(i.url), nil
+	case "body":
+		return starlark.String(i.body), nil
+	case "author":
+		return starlark.String(i.author), nil
+	case "assignees":
+		return modules.StringList(i.assignees), nil
+	case "labels":
+		return modules.StringList(i.labels), nil
+	case "created_at":
+		return modules.Time(i.createdAt), nil
+	case "updated_at":
+		return modules.Time(i.updatedAt), nil
+	case modules.AgeAttr:
+		return modules.Since(i.createdAt), nil
+	case modules.SinceUpdateAttr:
+		return modules.Since(i.updatedAt), nil
+	case "record":
+		return i.record, nil
+	default:
+		return nil, fmt.Errorf("unknown attribute %q", name)
+	}
+}
+
+func (i *Item) AttrNames() []string {
+	return append(i.BaseItem.AttrNames(),
+		"id",
+		"title",
+		"url",
+		"body",
+		"author",
+		"assignees",
+		"labels",
+		"created_at",
+		"updated_at",
+		modules.AgeAttr,
+		modules.SinceUpdateAttr,
+		"record",
+	)
+}
+
+// SetField sets the wranglr-specific field or custom field with the provided name.
+func (i *Item) SetField(name string, val starlark.Value) error {
+	return i.BaseItem.SetItemField(i, name, val)
+}
@@ -0,0 +1,343 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+// run executes src with the http module and the URL of server
+// as the globals http and url, returning the globals it defines.
+func run(t *testing.T, cache *httpcache.Cache, server *httptest.Server, src string) (starlark.StringDict, error) {
+	t.Helper()
+
+	predeclared := starlark.StringDict{
+		"http": &Module{Cache: cache},
+		"url":  starlark.String(server.URL),
+	}
+
+	thread := &starlark.Thread{Name: "test"}
+	return starlark.ExecFileOptions(&syntax.FileOptions{}, thread, "test.star", src, predeclared)
+}
+
+// recorder is a test server that records the requests it receives.
+type recorder struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+func newRecorder(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *recorder {
+	t.Helper()
+
+	rec := &recorder{}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		rec.mu.Lock()
+		rec.requests = append(rec.requests, r)
+		rec.bodies = append(rec.bodies, string(body))
+		rec.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(rec.Close)
+
+	return rec
+}
+
+func (rec *recorder) count() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.requests)
+}
+
+// pagesServer serves 3 pages of 2 records each, numbered 1 to 6, using
+// the page query parameter. Each page links to the next using the Link
+// header, a relative URL in "next" and a cursor in "meta.cursor", which
+// is passed back using the after query parameter.
+func pagesServer(t *testing.T) *recorder {
+	return newRecorder(t, func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		for _, param := range []string{"page", "after"} {
+			if v := r.URL.Query().Get(param); v != "" {
+				page, _ = strconv.Atoi(strings.TrimPrefix(v, "c"))
+			}
+		}
+
+		next, cursor := "null", "null"
+		if page < 3 {
+			next = fmt.Sprintf(`"%s?page=%d"`, r.URL.Path, page+1)
+			cursor = fmt.Sprintf(`"c%d"`, page+1)
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, r.URL.Path, page+1))
+		}
+
+		_, _ = fmt.Fprintf(w, `{"data": [{"n": %d}, {"n": %d}], "next": %s, "meta": {"cursor": %s}}`, page*2-1, page*2, next, cursor)
+	})
+}
+
+func TestFetchPagination(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		paginate   string
+		limit      int
+		want       []int
+		incomplete bool
+		requests   int
+	}{
+		{name: "link", paginate: `"link"`, want: []int{1, 2, 3, 4, 5, 6}, requests: 3},
+		{name: "next", paginate: `{"next": "next"}`, want: []int{1, 2, 3, 4, 5, 6}, requests: 3},
+		{name: "cursor", paginate: `{"cursor": "$.meta.cursor", "param": "after"}`, want: []int{1, 2, 3, 4, 5, 6}, requests: 3},
+		{name: "link with limit", paginate: `"link"`, limit: 3, want: []int{1, 2, 3}, incomplete: true, requests: 2},
+		{name: "next with limit", paginate: `{"next": "next"}`, limit: 2, want: []int{1, 2}, incomplete: true, requests: 1},
+		{name: "cursor with limit", paginate: `{"cursor": "meta.cursor", "param": "after"}`, limit: 5, want: []int{1, 2, 3, 4, 5}, incomplete: true, requests: 3},
+		{name: "limit of every record", paginate: `"link"`, limit: 6, want: []int{1, 2, 3, 4, 5, 6}, requests: 3},
+		{name: "no pagination with limit", limit: 1, want: []int{1}, incomplete: true, requests: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := pagesServer(t)
+
+			args := `url + "/items", records="data"`
+			if tc.paginate != "" {
+				args += ", paginate=" + tc.paginate
+			}
+			if tc.limit > 0 {
+				args += fmt.Sprintf(", limit=%d", tc.limit)
+			}
+
+			globals, err := run(t, nil, server.Server, "results = http.fetch("+args+")")
+			if err != nil {
+				t.Fatalf("fetching: %v", err)
+			}
+
+			results := globals["results"].(*modules.Results)
+			got := []int{}
+			for record := range results.List.Elements() {
+				n, _, _ := record.(*starlark.Dict).Get(starlark.String("n"))
+				i, _ := starlark.AsInt32(n)
+				got = append(got, i)
+			}
+
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got records %v, want %v", got, tc.want)
+			}
+			if results.Incomplete() != tc.incomplete {
+				t.Errorf("got incomplete %v, want %v", results.Incomplete(), tc.incomplete)
+			}
+			if n := server.count(); n != tc.requests {
+				t.Errorf("got %d requests, want %d", n, tc.requests)
+			}
+		})
+	}
+}
+
+func TestFetchTokenEnv(t *testing.T) {
+	server := newRecorder(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{}`)
+	})
+	t.Setenv("WRANGLR_TEST_TOKEN", "secret")
+
+	for _, tc := range []struct {
+		name   string
+		args   string
+		header string
+		want   string
+	}{
+		{name: "default", args: `token_env="WRANGLR_TEST_TOKEN"`, header: "Authorization", want: "Bearer secret"},
+		{name: "scheme", args: `token_env="WRANGLR_TEST_TOKEN", auth_scheme="Token"`, header: "Authorization", want: "Token secret"},
+		{name: "custom header", args: `token_env="WRANGLR_TEST_TOKEN", auth_header="X-Api-Key", auth_scheme=""`, header: "X-Api-Key", want: "secret"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := run(t, nil, server.Server, "http.fetch(url, "+tc.args+")"); err != nil {
+				t.Fatalf("fetching: %v", err)
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			if got := server.requests[len(server.requests)-1].Header.Get(tc.header); got != tc.want {
+				t.Errorf("got %s %q, want %q", tc.header, got, tc.want)
+			}
+		})
+	}
+
+	_, err := run(t, nil, server.Server, `http.fetch(url, token_env="WRANGLR_TEST_UNSET")`)
+	if err == nil || !strings.Contains(err.Error(), `"WRANGLR_TEST_UNSET" is not set`) {
+		t.Errorf("fetching with an unset token_env: got error %v", err)
+	}
+}
+
+func TestFetchPostCaching(t *testing.T) {
+	server := newRecorder(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"ok": true}`)
+	})
+	cache := httpcache.New(httpcache.Options{Dir: t.TempDir()})
+	t.Setenv("WRANGLR_TEST_TOKEN", "one")
+
+	fetch := func(args string) {
+		t.Helper()
+		if _, err := run(t, cache, server.Server, `http.fetch(url, method="post", cache_ttl="1h", `+args+`)`); err != nil {
+			t.Fatalf("fetching: %v", err)
+		}
+	}
+
+	fetch(`body={"q": "a"}`)
+	fetch(`body={"q": "a"}`)
+	if n := server.count(); n != 1 {
+		t.Fatalf("got %d requests for the same POST request, want it to be cached", n)
+	}
+
+	server.mu.Lock()
+	if got := server.requests[0].Method; got != http.MethodPost {
+		t.Errorf("got method %s, want %s", got, http.MethodPost)
+	}
+	if got := server.bodies[0]; got != `{"q":"a"}` {
+		t.Errorf("got body %s", got)
+	}
+	if got := server.requests[0].Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got Content-Type %q", got)
+	}
+	server.mu.Unlock()
+
+	// requests differing in their body or headers, including those
+	// used for authentication, never share cached responses
+	for i, args := range []string{
+		`body={"q": "b"}`,
+		`body={"q": "a"}, headers={"X-Tenant": "a"}`,
+		`body={"q": "a"}, token_env="WRANGLR_TEST_TOKEN", auth_header="X-Api-Key"`,
+	} {
+		fetch(args)
+		if n := server.count(); n != i+2 {
+			t.Errorf("fetching with %s: got %d requests, want %d", args, n, i+2)
+		}
+	}
+
+	t.Setenv("WRANGLR_TEST_TOKEN", "two")
+	fetch(`body={"q": "a"}, token_env="WRANGLR_TEST_TOKEN", auth_header="X-Api-Key"`)
+	if n := server.count(); n != 5 {
+		t.Errorf("got %d requests after changing the token, want 5", n)
+	}
+}
+
+func TestItems(t *testing.T) {
+	server := newRecorder(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[
+			{
+				"key": 42,
+				"summary": "Broken build",
+				"links": {"html": "https://example.com/42"},
+				"user": {"login": "octocat"},
+				"owners": [{"name": "a"}, {"name": "b"}],
+				"tags": "ci",
+				"created": "2024-05-01T10:00:00Z",
+				"updated": 1714600000,
+				"cvss": {"severity": "high"}
+			},
+			{
+				"summary": "No URL",
+				"owners": [],
+				"updated": 1714600000.5
+			}
+		]`)
+	})
+
+	globals, err := run(t, nil, server.Server, `
+results = http.items(
+    http.fetch(url, records="$"),
+    title="summary",
+    id="key",
+    url="links.html",
+    author="user.login",
+    assignees="owners[*].name",
+    labels="tags",
+    created_at="created",
+    updated_at="updated",
+    group="builds",
+    fields={"severity": "cvss.severity"},
+)
+first = results[0]
+summary = [str(first), bool(first), first.severity]
+`)
+	if err != nil {
+		t.Fatalf("mapping items: %v", err)
+	}
+
+	results := globals["results"].(*modules.Results)
+	if results.Len() != 2 {
+		t.Fatalf("got %d items, want 2", results.Len())
+	}
+
+	first := results.Index(0).(*Item)
+	if got := first.ID(); got != "42" {
+		t.Errorf("got ID %q, want %q", got, "42")
+	}
+	if got := first.Title(); got != "Broken build" {
+		t.Errorf("got title %q", got)
+	}
+	if got := first.URL(); got != "https://example.com/42" {
+		t.Errorf("got URL %q", got)
+	}
+	if got := first.Author(); got != "octocat" {
+		t.Errorf("got author %q", got)
+	}
+	if got := first.Assignees(); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("got assignees %v", got)
+	}
+	if got := first.Labels(); !slices.Equal(got, []string{"ci"}) {
+		t.Errorf("got labels %v", got)
+	}
+	if want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC); !first.CreatedAt().Equal(want) {
+		t.Errorf("got created_at %v, want %v", first.CreatedAt(), want)
+	}
+	if want := time.Unix(1714600000, 0); !first.UpdatedAt().Equal(want) {
+		t.Errorf("got updated_at %v, want %v", first.UpdatedAt(), want)
+	}
+	if got := first.Group(); got != "builds" {
+		t.Errorf("got group %q", got)
+	}
+
+	want := starlark.NewList([]starlark.Value{starlark.String("http 42"), starlark.True, starlark.String("high")})
+	if eq, err := starlark.Equal(globals["summary"], want); err != nil || !eq {
+		t.Errorf("got [str(item), bool(item), item.severity] %v, want %v", globals["summary"], want)
+	}
+
+	// the ID falls back to the title when there's no ID or URL
+	second := results.Index(1).(*Item)
+	if got := second.ID(); got != "No URL" {
+		t.Errorf("got ID %q, want the title", got)
+	}
+	if !second.CreatedAt().IsZero() {
+		t.Errorf("got created_at %v, want the zero time", second.CreatedAt())
+	}
+	if want := time.UnixMilli(1714600000500); !second.UpdatedAt().Equal(want) {
+		t.Errorf("got updated_at %v, want %v", second.UpdatedAt(), want)
+	}
+}
+
+func TestItemsInvalidTimestamp(t *testing.T) {
+	server := newRecorder(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"title": "a", "created": "yesterday"}, {"title": "b", "created": true}]`)
+	})
+
+	for i, want := range []string{`record 0: created_at: parsing time "yesterday"`, `record 0: created_at: must be an RFC 3339 string`} {
+		_, err := run(t, nil, server.Server, fmt.Sprintf(`http.items(http.fetch(url)[%d:], title="title", created_at="created")`, i))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("got error %v, want it to contain %q", err, want)
+		}
+	}
+}
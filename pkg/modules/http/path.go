@@ -0,0 +1,174 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.starlark.net/starlark"
+)
+
+// Path is a path to a field within a JSON value, written using
+// a subset of JSONPath (i.e "$.data.items", "fields.labels[*].name"
+// or "meta['next-cursor']"). The leading "$." is optional.
+type Path struct {
+	raw      string
+	segments []segment
+}
+
+type segment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// ParsePath parses a path. Keys are separated by dots or written
+// in brackets, either quoted or as a list index, and "[*]" matches
+// every element of a list.
+func ParsePath(raw string) (*Path, error) {
+	p := &Path{raw: raw}
+
+	rest := strings.TrimPrefix(strings.TrimSpace(raw), "$")
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty key", raw)
+			}
+			p.segments = append(p.segments, segment{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unterminated [", raw)
+			}
+
+			seg, err := parseBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid path %q: %w", raw, err)
+			}
+			p.segments = append(p.segments, seg)
+			rest = rest[end+1:]
+		default:
+			// the first key doesn't need a leading dot
+			if len(p.segments) > 0 {
+				return nil, fmt.Errorf("invalid path %q: expected . or [ before %q", raw, rest)
+			}
+			rest = "." + rest
+		}
+	}
+
+	return p, nil
+}
+
+func parseBracket(inner string) (segment, error) {
+	inner = strings.TrimSpace(inner)
+	switch {
+	case inner == "*":
+		return segment{wildcard: true}, nil
+	case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+		return segment{key: inner[1 : len(inner)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(inner)
+	if err != nil {
+		return segment{}, fmt.Errorf("%q is not *, a quoted key or a list index", inner)
+	}
+	return segment{index: index, isIndex: true}, nil
+}
+
+func (p *Path) String() string {
+	return p.raw
+}
+
+// Get returns the value at the path within value, and whether it was found.
+// Paths containing "[*]" return a list of the values found for each element.
+func (p *Path) Get(value starlark.Value) (starlark.Value, bool) {
+	return get(value, p.segments)
+}
+
+func get(value starlark.Value, segments []segment) (starlark.Value, bool) {
+	if len(segments) == 0 {
+		return value, true
+	}
+
+	seg, rest := segments[0], segments[1:]
+	switch {
+	case seg.wildcard:
+		indexable, ok := value.(starlark.Indexable)
+		if !ok {
+			return nil, false
+		}
+
+		elems := []starlark.Value{}
+		for i := range indexable.Len() {
+			if elem, ok := get(indexable.Index(i), rest); ok {
+				elems = append(elems, elem)
+			}
+		}
+		return starlark.NewList(elems), true
+	case seg.isIndex:
+		indexable, ok := value.(starlark.Indexable)
+		if !ok {
+			return nil, false
+		}
+
+		// negative indexes count from the end, like in Starlark
+		index := seg.index
+		if index < 0 {
+			index += indexable.Len()
+		}
+		if index < 0 || index >= indexable.Len() {
+			return nil, false
+		}
+		return get(indexable.Index(index), rest)
+	default:
+		mapping, ok := value.(starlark.Mapping)
+		if !ok {
+			return nil, false
+		}
+
+		elem, found, err := mapping.Get(starlark.String(seg.key))
+		if err != nil || !found {
+			return nil, false
+		}
+		return get(elem, rest)
+	}
+}
+
+// lookup returns the value at the path within the record, or None if the
+// path isn't set or the record doesn't contain the field.
+func (p *Path) lookup(record starlark.Value) starlark.Value {
+	if p.raw == "" {
+		return starlark.None
+	}
+
+	value, ok := p.Get(record)
+	if !ok {
+		return starlark.None
+	}
+	return value
+}
+
+// Unpack implements starlark.Unpacker so that paths
+// can be unpacked directly from builtin arguments.
+func (p *Path) Unpack(v starlark.Value) error {
+	raw, ok := starlark.AsString(v)
+	if !ok {
+		return fmt.Errorf("got %s, want a path string", v.Type())
+	}
+
+	parsed, err := ParsePath(raw)
+	if err != nil {
+		return err
+	}
+
+	*p = *parsed
+	return nil
+}
@@ -0,0 +1,252 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"go.starlark.net/starlark"
+)
+
+// Item is a work item from a source (i.e a GitHub issue or a Jira ticket).
+// Every source module returns items that implement this interface so that
+// they can be rendered, printed and interacted with without knowing which
+// source they came from.
+type Item interface {
+	starlark.HasSetField
+
+	// ID returns an identifier for the item that
+	// is unique within its source (i.e "PROJ-123").
+	ID() string
+
+	// Source returns the name of the source
+	// the item came from (i.e "github").
+	Source() string
+
+	URL() string
+	Title() string
+	Body() string
+	Author() string
+	Assignees() []string
+	Labels() []string
+	CreatedAt() time.Time
+	UpdatedAt() time.Time
+
+	// Status returns the wranglr-specific status assigned to the item.
+	Status() string
+
+	// Priority returns the wranglr-specific priority score assigned to the item.
+	Priority() int64
+
+	// Group returns the wranglr-specific group assigned to the item.
+	Group() string
+
+	// StaleSince returns the time the item was last successfully fetched
+	// from its source if it was served from the cache because the source
+	// couldn't be reached or wranglr is running offline, or the zero time otherwise.
+	StaleSince() time.Time
+
+	// Seen returns whether the item has been seen in the
+	// interactive output since it was last updated.
+	Seen() bool
+
+	// Snoozed returns whether the item was snoozed or dismissed in the
+	// interactive output and its snooze hasn't ended.
+	Snoozed() bool
+
+	// SnoozedUntil returns when the snooze of the item ends, or the zero time if
+	// the item isn't snoozed, is snoozed until it is updated or was dismissed.
+	SnoozedUntil() time.Time
+
+	// CustomFields returns the custom fields set on the item
+	// by the configuration, by name.
+	CustomFields() map[string]starlark.Value
+
+	// Raw returns the source-specific representation of
+	// the item, as returned by the source API.
+	Raw() any
+}
+
+const (
+	StatusAttr       = "status"
+	PriorityAttr     = "priority"
+	GroupAttr        = "group"
+	StaleAttr        = "stale"
+	StaleSinceAttr   = "stale_since"
+	SeenAttr         = "seen"
+	SnoozedAttr      = "snoozed"
+	SnoozedUntilAttr = "snoozed_until"
+)
+
+// BaseItem implements the wranglr-specific fields shared by all items.
+// Source-specific items embed it and defer to its Attr, AttrNames and
+// SetField methods for the wranglr-specific attributes.
+type BaseItem struct {
+	status     string
+	priority   int64
+	group      string
+	staleSince time.Time
+	seen       bool
+
+	snoozed      bool
+	snoozedUntil time.Time
+
+	// custom are the custom fields set by the configuration.
+	custom map[string]starlark.Value
+}
+
+func NewBaseItem(group string, staleSince time.Time) BaseItem {
+	return BaseItem{
+		group:      group,
+		staleSince: staleSince,
+	}
+}
+
+func (b *BaseItem) Status() string {
+	if b.status == "" {
+		return "Unknown"
+	}
+	return b.status
+}
+
+func (b *BaseItem) Priority() int64 {
+	return b.priority
+}
+
+func (b *BaseItem) Group() string {
+	if b.group == "" {
+		return "Unknown"
+	}
+	return b.group
+}
+
+func (b *BaseItem) StaleSince() time.Time {
+	return b.staleSince
+}
+
+func (b *BaseItem) Seen() bool {
+	return b.seen
+}
+
+// SetSeen sets whether the item has been seen since it was last updated.
+func (b *BaseItem) SetSeen(seen bool) {
+	b.seen = seen
+}
+
+func (b *BaseItem) Snoozed() bool {
+	return b.snoozed
+}
+
+func (b *BaseItem) SnoozedUntil() time.Time {
+	return b.snoozedUntil
+}
+
+// SetSnoozed sets whether the item is snoozed and when its snooze ends.
+func (b *BaseItem) SetSnoozed(snoozed bool, until time.Time) {
+	b.snoozed = snoozed
+	b.snoozedUntil = until
+}
+
+// Attr returns the value of the wranglr-specific attribute with the
+// provided name, or of the custom field with the provided name,
+// or nil if name is neither.
+func (b *BaseItem) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case StatusAttr:
+		return starlark.String(b.Status()), nil
+	case PriorityAttr:
+		return starlark.MakeInt64(b.Priority()), nil
+	case GroupAttr:
+		return starlark.String(b.Group()), nil
+	case StaleAttr:
+		return starlark.Bool(!b.staleSince.IsZero()), nil
+	case StaleSinceAttr:
+		return Time(b.staleSince), nil
+	case SeenAttr:
+		return starlark.Bool(b.seen), nil
+	case SnoozedAttr:
+		return starlark.Bool(b.snoozed), nil
+	case SnoozedUntilAttr:
+		return Time(b.snoozedUntil), nil
+	default:
+		return b.custom[name], nil
+	}
+}
+
+func (b *BaseItem) AttrNames() []string {
+	names := []string{
+		StatusAttr,
+		PriorityAttr,
+		GroupAttr,
+		StaleAttr,
+		StaleSinceAttr,
+		SeenAttr,
+		SnoozedAttr,
+		SnoozedUntilAttr,
+	}
+	return append(names, slices.Sorted(maps.Keys(b.custom))...)
+}
+
+// SetField sets the wranglr-specific field with the provided name.
+// It returns a starlark.NoSuchAttrError if name is not a settable
+// wranglr-specific field.
+func (b *BaseItem) SetField(name string, val starlark.Value) error {
+	switch name {
+	case StatusAttr:
+		str, ok := starlark.AsString(val)
+		if !ok {
+			return fmt.Errorf("status must be a string but was attempted to be set to type %q", val.Type())
+		}
+		b.status = str
+		return nil
+	case PriorityAttr:
+		intType, ok := val.(starlark.Int)
+		if !ok {
+			return fmt.Errorf("priority must be an integer but was attempted to be set to type %q", val.Type())
+		}
+		i64, ok := intType.Int64()
+		if !ok {
+			return errors.New("priority must be a valid int64, but was not")
+		}
+		b.priority = i64
+		return nil
+	case GroupAttr:
+		str, ok := starlark.AsString(val)
+		if !ok {
+			return fmt.Errorf("group must be a string but was attempted to be set to type %q", val.Type())
+		}
+		b.group = str
+		return nil
+	default:
+		return starlark.NoSuchAttrError(fmt.Sprintf("cannot set field %q", name))
+	}
+}
+
+// StringList converts a slice of strings to a Starlark list.
+func StringList(strs []string) *starlark.List {
+	elems := []starlark.Value{}
+	for _, str := range strs {
+		elems = append(elems, starlark.String(str))
+	}
+	return starlark.NewList(elems)
+}
+
+// AsStrings returns the elements of a list of strings, or an
+// error if the list contains an element that isn't a string.
+func AsStrings(list *starlark.List) ([]string, error) {
+	strs := []string{}
+	if list == nil {
+		return strs, nil
+	}
+
+	for elem := range list.Elements() {
+		str, ok := starlark.AsString(elem)
+		if !ok {
+			return nil, fmt.Errorf("must be a list of strings but contained type %q", elem.Type())
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
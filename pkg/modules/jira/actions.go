@@ -0,0 +1,163 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// apiPath returns the path of an endpoint for the flavor of the API.
+func apiPath(api API, path string) string {
+	if api == APICloud {
+		return "rest/api/3/" + path
+	}
+	return "rest/api/latest/" + path
+}
+
+func issuePath(api API, key, suffix string) string {
+	return apiPath(api, "issue/"+url.PathEscape(key)+suffix)
+}
+
+// Transition is a transition of an issue from its current status.
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   struct {
+		Name string `json:"name"`
+	} `json:"to"`
+}
+
+// Transitions fetches the transitions available for an issue.
+func (c *Client) Transitions(ctx context.Context, api API, key string) ([]Transition, error) {
+	result := struct {
+		Transitions []Transition `json:"transitions"`
+	}{}
+
+	err := c.get(ctx, issuePath(api, key, "/transitions"), url.Values{}, &result, &httpcache.Staleness{})
+	if err != nil {
+		return nil, fmt.Errorf("fetching transitions for %s: %w", key, err)
+	}
+
+	return result.Transitions, nil
+}
+
+// Transition transitions an issue using the transition with the name,
+// or the name of the status it transitions to, ignoring case.
+func (c *Client) Transition(ctx context.Context, api API, key, name string) error {
+	// cached transitions may no longer be available, so they are
+	// always revalidated rather than reused within the cache TTL
+	transitions, err := c.Transitions(httpcache.WithTTL(ctx, 0), api, key)
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.Name, name) || strings.EqualFold(transition.To.Name, name) {
+			return c.send(ctx, http.MethodPost, issuePath(api, key, "/transitions"), map[string]any{
+				"transition": map[string]any{"id": transition.ID},
+			})
+		}
+		names = append(names, transition.Name)
+	}
+
+	return fmt.Errorf("no transition %q for %s, available transitions are %q", name, key, names)
+}
+
+// AddComment comments on an issue. For Jira Server/Data Center the body is
+// wiki markup, for Jira Cloud it is plain text with paragraphs separated
+// by blank lines.
+func (c *Client) AddComment(ctx context.Context, api API, key, body string) error {
+	var commentBody any = body
+	if api == APICloud {
+		commentBody = textDocument(body)
+	}
+
+	return c.send(ctx, http.MethodPost, issuePath(api, key, "/comment"), map[string]any{
+		"body": commentBody,
+	})
+}
+
+// textDocument converts plain text to an Atlassian Document Format document.
+func textDocument(text string) map[string]any {
+	paragraphs := []any{}
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		content := []any{}
+		for i, line := range strings.Split(strings.Trim(paragraph, "\n"), "\n") {
+			if i > 0 {
+				content = append(content, map[string]any{"type": "hardBreak"})
+			}
+			if line != "" {
+				content = append(content, map[string]any{"type": "text", "text": line})
+			}
+		}
+		paragraphs = append(paragraphs, map[string]any{"type": "paragraph", "content": content})
+	}
+
+	return map[string]any{
+		"type":    "doc",
+		"version": 1,
+		"content": paragraphs,
+	}
+}
+
+// AssignSelf assigns an issue to the authenticated user.
+func (c *Client) AssignSelf(ctx context.Context, api API, key string) error {
+	// Jira Cloud identifies users by account ID
+	// whereas Jira Server/Data Center uses usernames
+	user := struct {
+		Name      string `json:"name"`
+		AccountID string `json:"accountId"`
+	}{}
+
+	err := c.get(ctx, apiPath(api, "myself"), url.Values{}, &user, &httpcache.Staleness{})
+	if err != nil {
+		return fmt.Errorf("fetching authenticated user: %w", err)
+	}
+
+	assignee := map[string]any{"name": user.Name}
+	if api == APICloud {
+		assignee = map[string]any{"accountId": user.AccountID}
+	}
+
+	return c.send(ctx, http.MethodPut, issuePath(api, key, "/assignee"), assignee)
+}
+
+// send sends a request that modifies a resource,
+// with body encoded as the JSON request body.
+func (c *Client) send(ctx context.Context, method, path string, body any) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshalling request body: %w", err)
+	}
+
+	uri := fmt.Sprintf("%s/%s", c.host, path)
+	req, err := http.NewRequestWithContext(ctx, method, uri, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Content-Type", "application/json")
+	setAuth(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status %q: %s", resp.Status, respBody)
+	}
+
+	return nil
+}
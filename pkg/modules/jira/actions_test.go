@@ -0,0 +1,259 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// recordedRequest is a write request received by a fakeJira.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// fakeJira is a Jira API that serves the transitions of an issue
+// and the authenticated user, and records every other request.
+type fakeJira struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	requests    []recordedRequest
+	transitions string
+}
+
+const transitionsResponse = `{"transitions": [
+	{"id": "11", "name": "Start progress", "to": {"name": "In Progress"}},
+	{"id": "21", "name": "Resolve", "to": {"name": "Done"}}
+]}`
+
+func newFakeJira(t *testing.T) *fakeJira {
+	t.Helper()
+
+	t.Setenv("WRANGLR_JIRA_TOKEN", "test-token")
+	t.Setenv("WRANGLR_JIRA_EMAIL", "")
+
+	fake := &fakeJira{transitions: transitionsResponse}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			switch r.URL.Path {
+			case "/rest/api/3/issue/WR-1/transitions", "/rest/api/latest/issue/WR-1/transitions":
+				fake.mu.Lock()
+				transitions := fake.transitions
+				fake.mu.Unlock()
+				_, _ = io.WriteString(w, transitions)
+			case "/rest/api/3/myself", "/rest/api/latest/myself":
+				_, _ = io.WriteString(w, `{"name": "jdoe", "accountId": "5b10ac8d82e05b22cc7d4ef5"}`)
+			default:
+				http.NotFound(w, r)
+			}
+			return
+		}
+
+		recorded := recordedRequest{Method: r.Method, Path: r.URL.Path}
+		if err := json.NewDecoder(r.Body).Decode(&recorded.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, recorded)
+		fake.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+func (f *fakeJira) setTransitions(transitions string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.transitions = transitions
+}
+
+func (f *fakeJira) recorded() []recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]recordedRequest{}, f.requests...)
+}
+
+func TestActions(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		action func(ctx context.Context, c *Client) error
+		want   recordedRequest
+	}{
+		{
+			name: "transition by name",
+			action: func(ctx context.Context, c *Client) error {
+				return c.Transition(ctx, APIServer, "WR-1", "start progress")
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/rest/api/latest/issue/WR-1/transitions",
+				Body:   map[string]any{"transition": map[string]any{"id": "11"}},
+			},
+		},
+		{
+			name: "transition by status",
+			action: func(ctx context.Context, c *Client) error {
+				return c.Transition(ctx, APICloud, "WR-1", "Done")
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/rest/api/3/issue/WR-1/transitions",
+				Body:   map[string]any{"transition": map[string]any{"id": "21"}},
+			},
+		},
+		{
+			name: "assign self on Jira Server",
+			action: func(ctx context.Context, c *Client) error {
+				return c.AssignSelf(ctx, APIServer, "WR-1")
+			},
+			want: recordedRequest{
+				Method: http.MethodPut,
+				Path:   "/rest/api/latest/issue/WR-1/assignee",
+				Body:   map[string]any{"name": "jdoe"},
+			},
+		},
+		{
+			name: "assign self on Jira Cloud",
+			action: func(ctx context.Context, c *Client) error {
+				return c.AssignSelf(ctx, APICloud, "WR-1")
+			},
+			want: recordedRequest{
+				Method: http.MethodPut,
+				Path:   "/rest/api/3/issue/WR-1/assignee",
+				Body:   map[string]any{"accountId": "5b10ac8d82e05b22cc7d4ef5"},
+			},
+		},
+		{
+			name: "add comment on Jira Server",
+			action: func(ctx context.Context, c *Client) error {
+				return c.AddComment(ctx, APIServer, "WR-1", "*Fixed* in main")
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/rest/api/latest/issue/WR-1/comment",
+				Body:   map[string]any{"body": "*Fixed* in main"},
+			},
+		},
+		{
+			name: "add comment on Jira Cloud",
+			action: func(ctx context.Context, c *Client) error {
+				return c.AddComment(ctx, APICloud, "WR-1", "Fixed in main.\nThanks!\n\nClosing.")
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/rest/api/3/issue/WR-1/comment",
+				Body: map[string]any{"body": map[string]any{
+					"type":    "doc",
+					"version": float64(1),
+					"content": []any{
+						map[string]any{"type": "paragraph", "content": []any{
+							map[string]any{"type": "text", "text": "Fixed in main."},
+							map[string]any{"type": "hardBreak"},
+							map[string]any{"type": "text", "text": "Thanks!"},
+						}},
+						map[string]any{"type": "paragraph", "content": []any{
+							map[string]any{"type": "text", "text": "Closing."},
+						}},
+					},
+				}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newFakeJira(t)
+			cache := httpcache.New(httpcache.Options{Dir: t.TempDir()})
+			client := NewClient(fake.URL, cache.Client())
+
+			if err := tc.action(context.Background(), client); err != nil {
+				t.Fatalf("running action: %v", err)
+			}
+
+			requests := fake.recorded()
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1: %+v", len(requests), requests)
+			}
+			if !reflect.DeepEqual(requests[0], tc.want) {
+				t.Errorf("got request %+v, want %+v", requests[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestTransitionUnknown(t *testing.T) {
+	fake := newFakeJira(t)
+	client := NewClient(fake.URL, fake.Client())
+
+	err := client.Transition(context.Background(), APIServer, "WR-1", "Reopen")
+	if err == nil {
+		t.Fatal("expected an error for an unknown transition")
+	}
+	if requests := fake.recorded(); len(requests) != 0 {
+		t.Errorf("expected no transition to be made, got %+v", requests)
+	}
+}
+
+func TestTransitionIgnoresCacheTTL(t *testing.T) {
+	fake := newFakeJira(t)
+	cache := httpcache.New(httpcache.Options{Dir: t.TempDir(), TTL: time.Hour})
+	client := NewClient(fake.URL, cache.Client())
+
+	if err := client.Transition(context.Background(), APIServer, "WR-1", "Resolve"); err != nil {
+		t.Fatalf("transitioning: %v", err)
+	}
+
+	// the workflow changed, so the cached transition ID is no longer valid
+	fake.setTransitions(`{"transitions": [{"id": "31", "name": "Resolve", "to": {"name": "Done"}}]}`)
+	if err := client.Transition(context.Background(), APIServer, "WR-1", "Resolve"); err != nil {
+		t.Fatalf("transitioning: %v", err)
+	}
+
+	requests := fake.recorded()
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2: %+v", len(requests), requests)
+	}
+	want := map[string]any{"transition": map[string]any{"id": "31"}}
+	if !reflect.DeepEqual(requests[1].Body, want) {
+		t.Errorf("got body %+v, want %+v", requests[1].Body, want)
+	}
+}
+
+func TestActionsOffline(t *testing.T) {
+	fake := newFakeJira(t)
+	dir := t.TempDir()
+
+	// the transitions are cached while online, so only the
+	// write that performs the transition is refused offline
+	online := httpcache.New(httpcache.Options{Dir: dir})
+	if _, err := NewClient(fake.URL, online.Client()).Transitions(context.Background(), APIServer, "WR-1"); err != nil {
+		t.Fatalf("fetching transitions: %v", err)
+	}
+
+	offline := httpcache.New(httpcache.Options{Dir: dir, Offline: true})
+	if err := NewClient(fake.URL, offline.Client()).Transition(context.Background(), APIServer, "WR-1", "Resolve"); err == nil {
+		t.Error("expected transitioning to fail while offline")
+	}
+	if requests := fake.recorded(); len(requests) != 0 {
+		t.Errorf("expected no requests to be sent while offline, got %+v", requests)
+	}
+}
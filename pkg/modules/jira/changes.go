@@ -0,0 +1,74 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+const (
+	TransitionAttr = "transition"
+	AddCommentAttr = "add_comment"
+)
+
+// TransitionBuiltin adds a change to the plan that transitions an item
+// using the transition with the name, or the name of the status it
+// transitions to. No change is added if the item already has the status.
+func TransitionBuiltin() modules.BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var item *Item
+		var name string
+
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+			"item", &item,
+			"name", &name,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "" {
+			return nil, fmt.Errorf("%s: name must not be empty", fn.Name())
+		}
+
+		if status := item.issue.Fields.Status; status != nil && strings.EqualFold(status.Name, name) {
+			return starlark.None, nil
+		}
+
+		modules.PlanFor(thread).Add(modules.NewChange(item, fmt.Sprintf("transition %q", name), func(ctx context.Context) error {
+			return item.client.Transition(ctx, item.api, item.issue.Key, name)
+		}))
+
+		return starlark.None, nil
+	}
+}
+
+// AddCommentBuiltin adds a change to the plan that comments on an item.
+func AddCommentBuiltin() modules.BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var item *Item
+		var body string
+
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+			"item", &item,
+			"body", &body,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if body == "" {
+			return nil, fmt.Errorf("%s: body must not be empty", fn.Name())
+		}
+
+		modules.PlanFor(thread).Add(modules.NewChange(item, fmt.Sprintf("comment %q", body), func(ctx context.Context) error {
+			return item.client.AddComment(ctx, item.api, item.issue.Key, body)
+		}))
+
+		return starlark.None, nil
+	}
+}
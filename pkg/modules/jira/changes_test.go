@@ -0,0 +1,113 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	gojira "github.com/andygrunwald/go-jira"
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+func TestChanges(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		attr   string
+		kwargs []starlark.Tuple
+		want   recordedRequest
+	}{
+		{
+			name: "transition",
+			attr: TransitionAttr,
+			kwargs: []starlark.Tuple{
+				{starlark.String("name"), starlark.String("Done")},
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/rest/api/latest/issue/WR-1/transitions",
+				Body:   map[string]any{"transition": map[string]any{"id": "21"}},
+			},
+		},
+		{
+			name: "add comment",
+			attr: AddCommentAttr,
+			kwargs: []starlark.Tuple{
+				{starlark.String("body"), starlark.String("*Fixed* in main")},
+			},
+			want: recordedRequest{
+				Method: http.MethodPost,
+				Path:   "/rest/api/latest/issue/WR-1/comment",
+				Body:   map[string]any{"body": "*Fixed* in main"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newFakeJira(t)
+			cache := httpcache.New(httpcache.Options{Dir: t.TempDir()})
+
+			item := newChangesItem()
+			item.client = NewClient(fake.URL, cache.Client())
+			item.api = APIServer
+
+			module := &Module{Cache: cache}
+			builtin, err := module.Attr(tc.attr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			thread := &starlark.Thread{Name: "test"}
+			plan := modules.PlanFor(thread)
+			if _, err := starlark.Call(thread, builtin, starlark.Tuple{item}, tc.kwargs); err != nil {
+				t.Fatalf("calling %s: %v", tc.attr, err)
+			}
+
+			// changes are only planned until they are applied
+			if requests := fake.recorded(); len(requests) != 0 {
+				t.Fatalf("expected no requests before applying, got %+v", requests)
+			}
+
+			changes := plan.Changes()
+			if len(changes) != 1 {
+				t.Fatalf("got %d changes, want 1", len(changes))
+			}
+			if err := changes[0].Apply(context.Background()); err != nil {
+				t.Fatalf("applying %q: %v", changes[0].Description, err)
+			}
+
+			requests := fake.recorded()
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1: %+v", len(requests), requests)
+			}
+			if !reflect.DeepEqual(requests[0], tc.want) {
+				t.Errorf("got request %+v, want %+v", requests[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestTransitionToCurrentStatus(t *testing.T) {
+	thread := &starlark.Thread{Name: "test"}
+	plan := modules.PlanFor(thread)
+
+	kwargs := []starlark.Tuple{{starlark.String("name"), starlark.String("in progress")}}
+	if _, err := starlark.Call(thread, starlark.NewBuiltin(TransitionAttr, TransitionBuiltin()), starlark.Tuple{newChangesItem()}, kwargs); err != nil {
+		t.Fatalf("calling %s: %v", TransitionAttr, err)
+	}
+
+	if changes := plan.Changes(); len(changes) != 0 {
+		t.Errorf("expected no changes transitioning to the current status, got %d", len(changes))
+	}
+}
+
+// newChangesItem returns an item for the issue WR-1, which is in progress.
+func newChangesItem() *Item {
+	return NewItem(Issue{Issue: gojira.Issue{
+		Key:    "WR-1",
+		Fields: &gojira.IssueFields{Status: &gojira.Status{Name: "In Progress"}},
+	}}, "https://jira.example.com/browse/WR-1", "", time.Time{})
+}
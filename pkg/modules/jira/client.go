@@ -1,60 +1,431 @@
 package jira
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	gojira "github.com/andygrunwald/go-jira"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// API is the flavor of the Jira search API to use.
+type API string
+
+const (
+	// APIAuto selects the search API based on the host.
+	// Hosts under atlassian.net use the Jira Cloud API,
+	// all other hosts use the Jira Server/Data Center API.
+	APIAuto API = "auto"
+
+	// APIServer uses the offset-based rest/api/latest/search
+	// endpoint supported by Jira Server and Data Center.
+	APIServer API = "server"
+
+	// APICloud uses the token-based rest/api/3/search/jql
+	// endpoint supported by Jira Cloud.
+	APICloud API = "cloud"
 )
 
+// DefaultPageSize is the number of results requested per page.
+// Jira instances may enforce a lower maximum, in which case
+// we page using whatever page size the instance returns.
+const DefaultPageSize = 100
+
 type Client struct {
 	host       string
 	httpClient *http.Client
 }
 
-func NewClient(host string) *Client {
+func NewClient(host string, httpClient *http.Client) *Client {
 	return &Client{
-		host:       host,
-		httpClient: http.DefaultClient,
+		host:       strings.TrimSuffix(host, "/"),
+		httpClient: httpClient,
 	}
 }
 
-func (c *Client) Issues(queries ...string) ([]gojira.Issue, error) {
-	jiraClient, err := gojira.NewClient(nil, c.host)
-	if err != nil {
-		return nil, err
+// SearchOptions configures how results are fetched
+// from the Jira search API.
+type SearchOptions struct {
+	// Limit is the maximum number of results to return per query.
+	// Values less than or equal to zero return all results.
+	Limit int
+
+	// Fields is the set of fields to return for each issue.
+	// When empty, all navigable fields are returned.
+	Fields []string
+
+	// API is the flavor of the search API to use.
+	// When empty, APIAuto is used.
+	API API
+}
+
+func (so SearchOptions) api(host string) API {
+	switch so.API {
+	case APIServer, APICloud:
+		return so.API
 	}
 
-	results := []gojira.Issue{}
+	u, err := url.Parse(host)
+	if err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net") {
+		return APICloud
+	}
+
+	return APIServer
+}
+
+func (so SearchOptions) done(count int) bool {
+	return so.Limit > 0 && count >= so.Limit
+}
+
+func (so SearchOptions) fields() string {
+	if len(so.Fields) == 0 {
+		return "*navigable"
+	}
+	return strings.Join(so.Fields, ",")
+}
+
+// Issue is a Jira issue along with any rich text fields that
+// were returned in the Atlassian Document Format (ADF) rather than
+// as plain strings, keyed by field name.
+type Issue struct {
+	gojira.Issue
+	Documents map[string]json.RawMessage `json:"documents,omitempty"`
+}
+
+// SearchResult is the aggregated result of one or more search queries.
+type SearchResult struct {
+	Issues []Issue
+
+	// Total is the total number of issues Jira reported as matching
+	// the queries. The Jira Cloud API does not report a total, so for
+	// Jira Cloud this is the number of issues that were fetched.
+	Total int
+
+	// Incomplete is true if the results were truncated due to
+	// the configured limit.
+	Incomplete bool
+
+	// StaleSince is the time the oldest of the results was last successfully
+	// fetched from Jira if any of the results were served from the cache
+	// because Jira couldn't be reached or wranglr is running offline.
+	// It is the zero time if all results are fresh.
+	StaleSince time.Time
+}
+
+func (c *Client) Issues(ctx context.Context, opts SearchOptions, queries ...string) (*SearchResult, error) {
+	out := &SearchResult{
+		Issues: []Issue{},
+	}
+	staleness := &httpcache.Staleness{}
 
 	for _, query := range queries {
-		u := url.URL{
-			Path: "rest/api/latest/search",
+		var err error
+		switch opts.api(c.host) {
+		case APICloud:
+			err = c.searchCloud(ctx, opts, query, out, staleness)
+		default:
+			err = c.searchServer(ctx, opts, query, out, staleness)
 		}
+
+		if err != nil {
+			return nil, fmt.Errorf("fetching jira issues using query %q: %w", query, err)
+		}
+	}
+
+	out.StaleSince = staleness.Since()
+	return out, nil
+}
+
+type serverSearchResult struct {
+	StartAt    int               `json:"startAt"`
+	MaxResults int               `json:"maxResults"`
+	Total      int               `json:"total"`
+	Issues     []json.RawMessage `json:"issues,omitempty"`
+}
+
+func (c *Client) searchServer(ctx context.Context, opts SearchOptions, query string, out *SearchResult, staleness *httpcache.Staleness) error {
+	issues := []Issue{}
+	total := 0
+
+	for {
 		uv := url.Values{}
 		uv.Add("jql", query)
-		u.RawQuery = uv.Encode()
+		uv.Add("startAt", strconv.Itoa(len(issues)))
+		uv.Add("maxResults", strconv.Itoa(DefaultPageSize))
+		uv.Add("fields", opts.fields())
+
+		page := &serverSearchResult{}
+		err := c.get(ctx, "rest/api/latest/search", uv, page, staleness)
+		if err != nil {
+			return err
+		}
+
+		total = page.Total
+
+		decoded, err := decodeIssues(page.Issues)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, decoded...)
+
+		if len(decoded) == 0 || len(issues) >= total || opts.done(len(issues)) {
+			break
+		}
+	}
+
+	if opts.Limit > 0 && len(issues) > opts.Limit {
+		issues = issues[:opts.Limit]
+	}
+
+	if len(issues) < total {
+		out.Incomplete = true
+	}
 
-		req, _ := jiraClient.NewRequest("GET", u.String(), nil)
+	out.Total += total
+	out.Issues = append(out.Issues, issues...)
+	return nil
+}
+
+type cloudSearchResult struct {
+	NextPageToken string            `json:"nextPageToken"`
+	IsLast        bool              `json:"isLast"`
+	Issues        []json.RawMessage `json:"issues,omitempty"`
+}
 
-		if token := getAuth(); token != "" {
-			req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+func (c *Client) searchCloud(ctx context.Context, opts SearchOptions, query string, out *SearchResult, staleness *httpcache.Staleness) error {
+	issues := []Issue{}
+	nextPageToken := ""
+	isLast := false
+
+	for !isLast {
+		uv := url.Values{}
+		uv.Add("jql", query)
+		uv.Add("maxResults", strconv.Itoa(DefaultPageSize))
+		uv.Add("fields", opts.fields())
+		if nextPageToken != "" {
+			uv.Add("nextPageToken", nextPageToken)
 		}
 
-		searchRes := &searchResult{}
-		resp, err := jiraClient.Do(req, searchRes)
+		page := &cloudSearchResult{}
+		err := c.get(ctx, "rest/api/3/search/jql", uv, page, staleness)
 		if err != nil {
-			return nil, fmt.Errorf("fetching jira issues using query %q: %w", query, err)
+			return err
+		}
+
+		decoded, err := decodeIssues(page.Issues)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, decoded...)
+
+		nextPageToken = page.NextPageToken
+		isLast = page.IsLast || nextPageToken == "" || len(decoded) == 0
+
+		if opts.done(len(issues)) {
+			break
+		}
+	}
+
+	if opts.Limit > 0 && len(issues) > opts.Limit {
+		issues = issues[:opts.Limit]
+		isLast = false
+	}
+
+	if !isLast {
+		out.Incomplete = true
+	}
+
+	out.Total += len(issues)
+	out.Issues = append(out.Issues, issues...)
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, into any, staleness *httpcache.Staleness) error {
+	uri := fmt.Sprintf("%s/%s?%s", c.host, path, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	req.Header.Add("Accept", "application/json")
+	setAuth(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("doing http request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	staleness.Observe(resp)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("request failed with status %q: %s", resp.Status, bodyBytes)
+	}
+
+	err = json.Unmarshal(bodyBytes, into)
+	if err != nil {
+		return fmt.Errorf("unmarshalling results: %w", err)
+	}
+
+	return nil
+}
+
+// decodeIssues decodes raw issues from a search response.
+// The Jira Cloud v3 API returns rich text fields as Atlassian
+// Document Format objects rather than strings, which the go-jira
+// types can't decode, so those are extracted before decoding.
+func decodeIssues(raw []json.RawMessage) ([]Issue, error) {
+	issues := []Issue{}
+	for _, rawIssue := range raw {
+		issue, err := decodeIssue(rawIssue)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func decodeIssue(raw json.RawMessage) (Issue, error) {
+	issue := Issue{
+		Documents: map[string]json.RawMessage{},
+	}
+
+	rawIssue := map[string]json.RawMessage{}
+	err := json.Unmarshal(raw, &rawIssue)
+	if err != nil {
+		return issue, fmt.Errorf("unmarshalling issue: %w", err)
+	}
+
+	if rawFields, ok := rawIssue["fields"]; ok {
+		fields := map[string]json.RawMessage{}
+		err = json.Unmarshal(rawFields, &fields)
+		if err != nil {
+			return issue, fmt.Errorf("unmarshalling issue fields: %w", err)
 		}
-		defer func() { _ = resp.Body.Close() }()
 
-		results = append(results, searchRes.Issues...)
+		for name, value := range fields {
+			if isDocument(value) {
+				issue.Documents[name] = value
+				fields[name] = json.RawMessage("null")
+				continue
+			}
 
+			if bytes.Contains(value, []byte(`"doc"`)) {
+				fields[name], err = stripDocuments(value)
+				if err != nil {
+					return issue, fmt.Errorf("processing issue field %q: %w", name, err)
+				}
+			}
+		}
+
+		rawIssue["fields"], err = json.Marshal(fields)
+		if err != nil {
+			return issue, fmt.Errorf("marshalling issue fields: %w", err)
+		}
 	}
 
-	return results, nil
+	sanitized, err := json.Marshal(rawIssue)
+	if err != nil {
+		return issue, fmt.Errorf("marshalling issue: %w", err)
+	}
+
+	err = json.Unmarshal(sanitized, &issue.Issue)
+	if err != nil {
+		return issue, fmt.Errorf("unmarshalling issue: %w", err)
+	}
+
+	if issue.Fields == nil {
+		issue.Fields = &gojira.IssueFields{}
+	}
+
+	return issue, nil
+}
+
+type document struct {
+	Type    string `json:"type"`
+	Version *int   `json:"version"`
+}
+
+func isDocument(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+
+	doc := document{}
+	if err := json.Unmarshal(trimmed, &doc); err != nil {
+		return false
+	}
+
+	return doc.Type == "doc" && doc.Version != nil
+}
+
+// stripDocuments replaces any Atlassian Document Format objects
+// nested within the provided JSON value with empty strings.
+func stripDocuments(raw json.RawMessage) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(stripDocumentValues(value))
+}
+
+func stripDocumentValues(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if v["type"] == "doc" && v["version"] != nil {
+			return ""
+		}
+		for key, elem := range v {
+			v[key] = stripDocumentValues(elem)
+		}
+		return v
+	case []any:
+		for i, elem := range v {
+			v[i] = stripDocumentValues(elem)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func setAuth(req *http.Request) {
+	token := getAuth()
+	if token == "" {
+		return
+	}
+
+	// Jira Cloud API tokens are used with basic
+	// authentication alongside the account email,
+	// whereas Jira Server/DC uses personal access tokens
+	// as bearer tokens.
+	if email := getEmail(); email != "" {
+		req.SetBasicAuth(email, token)
+		return
+	}
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
 }
 
 // TODO: implement some kind of multi-host authentication
@@ -65,3 +436,7 @@ func getAuth() string {
 	auth := os.Getenv("WRANGLR_JIRA_TOKEN")
 	return auth
 }
+
+func getEmail() string {
+	return os.Getenv("WRANGLR_JIRA_EMAIL")
+}
@@ -0,0 +1,57 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	gojira "github.com/andygrunwald/go-jira"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
+)
+
+// Comment is a comment on a Jira issue.
+type Comment struct {
+	Author  *gojira.User `json:"author"`
+	Created gojira.Time  `json:"created"`
+
+	// Body is the comment as wiki markup for Jira Server/Data Center,
+	// or an Atlassian Document Format document for Jira Cloud.
+	Body json.RawMessage `json:"body"`
+}
+
+type commentsPage struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Comments   []Comment `json:"comments"`
+}
+
+// Comments fetches all comments on an issue, oldest first,
+// using the flavor of the API the issue was searched with.
+func (c *Client) Comments(ctx context.Context, api API, key string, staleness *httpcache.Staleness) ([]Comment, error) {
+	path := issuePath(api, key, "/comment")
+
+	comments := []Comment{}
+	for {
+		uv := url.Values{}
+		uv.Add("startAt", strconv.Itoa(len(comments)))
+		uv.Add("maxResults", strconv.Itoa(DefaultPageSize))
+
+		page := &commentsPage{}
+		err := c.get(ctx, path, uv, page, staleness)
+		if err != nil {
+			return nil, fmt.Errorf("fetching comments for %s: %w", key, err)
+		}
+
+		comments = append(comments, page.Comments...)
+
+		if len(page.Comments) == 0 || len(comments) >= page.Total {
+			break
+		}
+	}
+
+	return comments, nil
+}
@@ -0,0 +1,57 @@
+package jira
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+type documentNode struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text"`
+	Content []documentNode `json:"content"`
+}
+
+// blockNodes are Atlassian Document Format node types
+// that should be separated from their siblings by a newline
+// when converted to plain text.
+var blockNodes = map[string]bool{
+	"paragraph":   true,
+	"heading":     true,
+	"codeBlock":   true,
+	"blockquote":  true,
+	"listItem":    true,
+	"rule":        true,
+	"tableRow":    true,
+	"panel":       true,
+	"mediaSingle": true,
+}
+
+// documentText returns the plain text content of an
+// Atlassian Document Format document.
+func documentText(raw json.RawMessage) string {
+	root := documentNode{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return ""
+	}
+
+	var out strings.Builder
+	writeDocumentText(&out, root)
+	return strings.TrimSpace(out.String())
+}
+
+func writeDocumentText(out *strings.Builder, node documentNode) {
+	switch node.Type {
+	case "text":
+		out.WriteString(node.Text)
+	case "hardBreak":
+		out.WriteString("\n")
+	}
+
+	for _, child := range node.Content {
+		writeDocumentText(out, child)
+	}
+
+	if blockNodes[node.Type] {
+		out.WriteString("\n")
+	}
+}
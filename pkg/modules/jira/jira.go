@@ -2,8 +2,10 @@ package jira
 
 import (
 	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/httpcache"
 )
 
-func New() (string, starlark.Value) {
-	return "jira", &Module{}
+func New(cache *httpcache.Cache) (string, starlark.Value) {
+	return "jira", &Module{Cache: cache}
 }
@@ -0,0 +1,351 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// node is a node in an Atlassian Document Format document.
+// See https://developer.atlassian.com/cloud/jira/platform/apis/document/structure/
+type node struct {
+	Type    string         `json:"type"`
+	Text    string         `json:"text"`
+	Attrs   map[string]any `json:"attrs"`
+	Marks   []mark         `json:"marks"`
+	Content []node         `json:"content"`
+}
+
+type mark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs"`
+}
+
+func (n node) attr(name string) string {
+	switch v := n.Attrs[name].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// FromADF converts an Atlassian Document Format document,
+// as returned by the Jira Cloud v3 API, to Markdown.
+func FromADF(raw json.RawMessage) (string, error) {
+	root := node{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return "", fmt.Errorf("unmarshalling document: %w", err)
+	}
+
+	return strings.TrimSpace(blocks(root.Content, "")), nil
+}
+
+// blocks renders block nodes, separated by blank lines.
+// Every line is prefixed with prefix, which is used for
+// indenting nested content (i.e within list items or quotes).
+func blocks(nodes []node, prefix string) string {
+	rendered := []string{}
+	for _, n := range nodes {
+		if out := block(n, prefix); out != "" {
+			rendered = append(rendered, out)
+		}
+	}
+	return strings.Join(rendered, "\n"+strings.TrimRight(prefix, " ")+"\n")
+}
+
+func block(n node, prefix string) string {
+	switch n.Type {
+	case "paragraph":
+		return prefixLines(inline(n.Content), prefix)
+	case "heading":
+		level, _ := strconv.Atoi(n.attr("level"))
+		level = min(max(level, 1), 6)
+		return prefix + strings.Repeat("#", level) + " " + strings.ReplaceAll(inline(n.Content), "  \n", " ")
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		return list(n, prefix)
+	case "codeBlock":
+		return prefixLines(codeBlock(n.attr("language"), plainText(n)), prefix)
+	case "blockquote":
+		return blocks(n.Content, prefix+"> ")
+	case "panel":
+		label := panelLabels[n.attr("panelType")]
+		if label == "" {
+			label = "Note"
+		}
+		return prefix + "> **" + label + ":**\n" + strings.TrimRight(prefix, " ") + "> \n" + blocks(n.Content, prefix+"> ")
+	case "expand", "nestedExpand":
+		out := blocks(n.Content, prefix)
+		if title := n.attr("title"); title != "" {
+			out = prefix + "**" + escape(title) + "**\n" + strings.TrimRight(prefix, " ") + "\n" + out
+		}
+		return out
+	case "rule":
+		return prefix + "---"
+	case "table":
+		return prefixLines(table(n), prefix)
+	case "mediaSingle", "mediaGroup":
+		return prefixLines(inline(n.Content), prefix)
+	default:
+		// unknown block types are rendered as their content,
+		// which is better than dropping them entirely
+		if containsBlock(n.Content) {
+			return blocks(n.Content, prefix)
+		}
+		return prefixLines(inline([]node{n}), prefix)
+	}
+}
+
+var panelLabels = map[string]string{
+	"info":    "Info",
+	"note":    "Note",
+	"warning": "Warning",
+	"success": "Success",
+	"error":   "Error",
+	"tip":     "Tip",
+}
+
+func isBlock(n node) bool {
+	switch n.Type {
+	case "paragraph", "heading", "bulletList", "orderedList", "taskList", "decisionList",
+		"codeBlock", "blockquote", "panel", "expand", "nestedExpand", "rule", "table", "mediaSingle", "mediaGroup":
+		return true
+	}
+	return false
+}
+
+// containsBlock returns whether any of the nodes, or their descendants,
+// are block nodes, such as the paragraphs within the columns of a layout.
+func containsBlock(nodes []node) bool {
+	for _, n := range nodes {
+		if isBlock(n) || containsBlock(n.Content) {
+			return true
+		}
+	}
+	return false
+}
+
+func list(n node, prefix string) string {
+	start, err := strconv.Atoi(n.attr("order"))
+	if err != nil || start < 1 {
+		start = 1
+	}
+
+	items := []string{}
+	for i, item := range n.Content {
+		marker := "- "
+		switch {
+		case n.Type == "orderedList":
+			marker = fmt.Sprintf("%d. ", start+i)
+		case item.Type == "taskItem" && item.attr("state") == "DONE":
+			marker = "- [x] "
+		case item.Type == "taskItem":
+			marker = "- [ ] "
+		}
+
+		// task and decision items contain inline content directly
+		// rather than paragraphs, so wrap them in one
+		content := item.Content
+		if len(content) > 0 && !isBlock(content[0]) {
+			content = []node{{Type: "paragraph", Content: content}}
+		}
+
+		indent := prefix + strings.Repeat(" ", len(marker))
+		rendered := blocks(content, indent)
+		items = append(items, prefix+marker+strings.TrimPrefix(rendered, indent))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func table(n node) string {
+	rows := [][]string{}
+	header := false
+	for i, row := range n.Content {
+		cells := []string{}
+		for _, cell := range row.Content {
+			if i == 0 && cell.Type == "tableHeader" {
+				header = true
+			}
+			cells = append(cells, tableCell(cell))
+		}
+		rows = append(rows, cells)
+	}
+
+	return markdownTable(rows, header)
+}
+
+// tableCell renders the content of a table cell on a single line,
+// as Markdown tables don't support multi-line cells.
+func tableCell(cell node) string {
+	parts := []string{}
+	for _, child := range cell.Content {
+		var text string
+		if isBlock(child) {
+			text = inline(child.Content)
+		} else {
+			text = inline([]node{child})
+		}
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.ReplaceAll(strings.Join(parts, " "), "  \n", " ")
+}
+
+// markdownTable renders rows as a Markdown table. Markdown tables
+// require a header row, so when the first row isn't a header an
+// empty one is used. Pipes within cells are escaped.
+func markdownTable(rows [][]string, header bool) string {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	if !header {
+		rows = append([][]string{make([]string, columns)}, rows...)
+	}
+
+	var out strings.Builder
+	for i, row := range rows {
+		out.WriteString("|")
+		for c := range columns {
+			cell := ""
+			if c < len(row) {
+				cell = strings.ReplaceAll(strings.TrimSpace(row[c]), "|", `\|`)
+			}
+			out.WriteString(" " + cell + " |")
+		}
+		out.WriteString("\n")
+
+		if i == 0 {
+			out.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+func inline(nodes []node) string {
+	var out strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			out.WriteString(marked(n.Text, n.Marks))
+		case "hardBreak":
+			out.WriteString("  \n")
+		case "mention":
+			text := n.attr("text")
+			if text == "" {
+				text = "@" + n.attr("id")
+			}
+			if !strings.HasPrefix(text, "@") {
+				text = "@" + text
+			}
+			out.WriteString("**" + escape(text) + "**")
+		case "emoji":
+			text := n.attr("text")
+			if text == "" {
+				text = n.attr("shortName")
+			}
+			out.WriteString(text)
+		case "inlineCard", "blockCard", "embedCard":
+			if url := n.attr("url"); url != "" {
+				out.WriteString("<" + url + ">")
+			}
+		case "status":
+			out.WriteString("`" + strings.ToUpper(n.attr("text")) + "`")
+		case "date":
+			out.WriteString(date(n.attr("timestamp")))
+		case "media", "mediaInline":
+			name := n.attr("alt")
+			if name == "" {
+				name = "attachment"
+			}
+			out.WriteString("_[" + escape(name) + "]_")
+		case "placeholder":
+			out.WriteString(escape(n.attr("text")))
+		default:
+			out.WriteString(inline(n.Content))
+		}
+	}
+	return out.String()
+}
+
+// marked renders text with its marks applied.
+func marked(text string, marks []mark) string {
+	link := ""
+	code := false
+	for _, m := range marks {
+		switch m.Type {
+		case "code":
+			code = true
+		case "link":
+			if href, ok := m.Attrs["href"].(string); ok {
+				link = href
+			}
+		}
+	}
+
+	if code {
+		text = codeSpan(text)
+	} else {
+		text = escape(text)
+	}
+
+	// leading and trailing whitespace prevents emphasis from
+	// being recognized, so it is moved outside of the markers
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+	text = trimmed
+
+	for _, m := range marks {
+		switch m.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "_" + text + "_"
+		case "strike":
+			text = "~~" + text + "~~"
+		}
+	}
+
+	if link != "" {
+		text = "[" + text + "](" + link + ")"
+	}
+
+	return leading + text + trailing
+}
+
+func plainText(n node) string {
+	var out strings.Builder
+	if n.Type == "text" {
+		out.WriteString(n.Text)
+	}
+	if n.Type == "hardBreak" {
+		out.WriteString("\n")
+	}
+	for _, child := range n.Content {
+		out.WriteString(plainText(child))
+	}
+	return out.String()
+}
+
+func date(timestamp string) string {
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return timestamp
+	}
+	return time.UnixMilli(ms).UTC().Format(time.DateOnly)
+}
@@ -0,0 +1,69 @@
+// Package markdown converts the rich text formats used by Jira,
+// wiki markup (Jira Server/Data Center) and the Atlassian Document
+// Format (Jira Cloud), to Markdown so that it can be rendered the
+// same way as Markdown from other sources.
+package markdown
+
+import (
+	"strings"
+)
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+)
+
+// escape escapes characters in text that
+// would otherwise be interpreted as Markdown.
+func escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// codeSpan renders text as inline code, using a
+// backtick fence longer than any run of backticks in text.
+func codeSpan(text string) string {
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+
+	return fence + text + fence
+}
+
+// codeBlock renders text as a fenced code block, using a
+// fence longer than any run of backticks in text.
+func codeBlock(language, text string) string {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+
+	return fence + language + "\n" + strings.TrimSuffix(text, "\n") + "\n" + fence
+}
+
+// prefixLines prefixes every line of text with prefix.
+func prefixLines(text, prefix string) string {
+	if prefix == "" || text == "" {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+			continue
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,66 @@
+package markdown
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the expected Markdown in testdata")
+
+func TestConvert(t *testing.T) {
+	fromWiki := func(input []byte) (string, error) { return FromWiki(string(input)), nil }
+	fromADF := func(input []byte) (string, error) { return FromADF(input) }
+
+	for _, tc := range []struct {
+		input   string
+		convert func(input []byte) (string, error)
+	}{
+		{input: "wiki/headings.txt", convert: fromWiki},
+		{input: "wiki/lists.txt", convert: fromWiki},
+		{input: "wiki/tables.txt", convert: fromWiki},
+		{input: "wiki/macros.txt", convert: fromWiki},
+		{input: "wiki/inline.txt", convert: fromWiki},
+		{input: "adf/headings.json", convert: fromADF},
+		{input: "adf/lists.json", convert: fromADF},
+		{input: "adf/tables.json", convert: fromADF},
+		{input: "adf/blocks.json", convert: fromADF},
+		{input: "adf/inline.json", convert: fromADF},
+		{input: "adf/unknown.json", convert: fromADF},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			input, err := os.ReadFile(filepath.Join("testdata", tc.input))
+			if err != nil {
+				t.Fatalf("reading input: %v", err)
+			}
+
+			got, err := tc.convert(input)
+			if err != nil {
+				t.Fatalf("converting: %v", err)
+			}
+
+			expectedPath := filepath.Join("testdata", strings.TrimSuffix(tc.input, filepath.Ext(tc.input))+".md")
+			if *update {
+				if err := os.WriteFile(expectedPath, []byte(got+"\n"), 0o644); err != nil {
+					t.Fatalf("updating expected Markdown: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(expectedPath)
+			if err != nil {
+				t.Fatalf("reading expected Markdown: %v", err)
+			}
+			if got != strings.TrimSuffix(string(want), "\n") {
+				t.Errorf("Markdown doesn't match %s (run with -update to update it):\ngot:\n%s\nwant:\n%s", expectedPath, got, want)
+			}
+		})
+	}
+}
+
+func TestFromADFInvalid(t *testing.T) {
+	if _, err := FromADF([]byte(`{"type": `)); err == nil {
+		t.Error("expected an error for an invalid document")
+	}
+}
@@ -0,0 +1,408 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	headingRegex   = regexp.MustCompile(`^h([1-6])\.\s*(.*)$`)
+	listRegex      = regexp.MustCompile(`^([*#-]+)\s+(.*)$`)
+	ruleRegex      = regexp.MustCompile(`^-{4,}$`)
+	blockOpenRegex = regexp.MustCompile(`^\{(code|noformat|quote|panel)(?::([^}]*))?\}`)
+	macroRegex     = regexp.MustCompile(`^\{(color|anchor)(:[^}]*)?\}$`)
+)
+
+type blockKind int
+
+const (
+	kindNone blockKind = iota
+	kindParagraph
+	kindList
+	kindTable
+	kindOther
+)
+
+// wikiWriter accumulates converted Markdown lines, separating
+// different kinds of blocks with blank lines as Markdown requires.
+type wikiWriter struct {
+	lines []string
+	last  blockKind
+}
+
+func (w *wikiWriter) write(kind blockKind, lines ...string) {
+	switch {
+	case w.last == kindNone:
+	case kind == kindParagraph && w.last == kindParagraph:
+		// Jira renders newlines within a paragraph as line
+		// breaks, whereas Markdown joins the lines together
+		w.lines[len(w.lines)-1] += "  "
+	case kind == w.last && kind != kindOther:
+	default:
+		w.lines = append(w.lines, "")
+	}
+
+	w.lines = append(w.lines, lines...)
+	w.last = kind
+}
+
+func (w *wikiWriter) blank() {
+	if w.last != kindNone {
+		w.lines = append(w.lines, "")
+	}
+	w.last = kindNone
+}
+
+// FromWiki converts Jira wiki markup, as used by
+// Jira Server and Data Center, to Markdown.
+// See https://jira.atlassian.com/secure/WikiRendererHelpAction.jspa?section=all
+func FromWiki(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	w := &wikiWriter{}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" {
+			w.blank()
+			continue
+		}
+
+		if matches := blockOpenRegex.FindStringSubmatch(trimmed); matches != nil {
+			macro, params := matches[1], matches[2]
+			content, consumed := macroContent(lines[i:], macro, trimmed[len(matches[0]):])
+			i += consumed
+
+			switch macro {
+			case "code", "noformat":
+				w.write(kindOther, codeBlock(codeLanguage(macro, params), content))
+			case "quote":
+				w.write(kindOther, prefixLines(FromWiki(content), "> "))
+			case "panel":
+				quoted := prefixLines(FromWiki(content), "> ")
+				if title := macroParam(params, "title"); title != "" {
+					quoted = "> **" + wikiInline(title) + "**\n>\n" + quoted
+				}
+				w.write(kindOther, quoted)
+			}
+			continue
+		}
+
+		if matches := headingRegex.FindStringSubmatch(trimmed); matches != nil {
+			level, _ := strconv.Atoi(matches[1])
+			w.write(kindOther, strings.Repeat("#", level)+" "+wikiInline(matches[2]))
+			continue
+		}
+
+		if ruleRegex.MatchString(trimmed) {
+			w.write(kindOther, "---")
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(trimmed, "bq. "); ok {
+			w.write(kindOther, "> "+wikiInline(rest))
+			continue
+		}
+
+		// only a single - is a list marker, as -- and --- are dashes
+		if matches := listRegex.FindStringSubmatch(trimmed); matches != nil && !strings.Contains(matches[1][1:], "-") {
+			w.write(kindList, listItem(matches[1], wikiInline(matches[2])))
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "|") {
+			rows := [][]string{}
+			header := strings.HasPrefix(trimmed, "||")
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, tableRow(strings.TrimSpace(lines[i])))
+			}
+			i--
+			w.write(kindTable, markdownTable(rows, header))
+			continue
+		}
+
+		w.write(kindParagraph, wikiInline(trimmed))
+	}
+
+	return strings.TrimSpace(strings.Join(w.lines, "\n"))
+}
+
+// macroContent returns the content of a block macro (i.e {code}...{code})
+// that starts on the first of lines, with rest being the remainder of the
+// first line after the opening tag. It also returns the number of
+// additional lines consumed by the macro.
+func macroContent(lines []string, macro, rest string) (string, int) {
+	closing := "{" + macro + "}"
+
+	if content, _, ok := strings.Cut(rest, closing); ok {
+		return content, 0
+	}
+
+	content := []string{}
+	if strings.TrimSpace(rest) != "" {
+		content = append(content, rest)
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if before, _, ok := strings.Cut(lines[i], closing); ok {
+			if strings.TrimSpace(before) != "" {
+				content = append(content, before)
+			}
+			return strings.Join(content, "\n"), i
+		}
+		content = append(content, lines[i])
+	}
+
+	// unterminated macros extend to the end of the text
+	return strings.Join(content, "\n"), len(lines) - 1
+}
+
+// codeLanguage returns the language of a code macro, which is either
+// the first parameter (i.e {code:java}) or the language parameter
+// (i.e {code:language=java|title=Example.java}).
+func codeLanguage(macro, params string) string {
+	if macro != "code" || params == "" {
+		return ""
+	}
+
+	if language := macroParam(params, "language"); language != "" {
+		return language
+	}
+
+	first, _, _ := strings.Cut(params, "|")
+	if strings.Contains(first, "=") {
+		return ""
+	}
+	return first
+}
+
+func macroParam(params, name string) string {
+	for _, param := range strings.Split(params, "|") {
+		if value, ok := strings.CutPrefix(param, name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// listItem renders a list item, where markers is the Jira list
+// markers for the item (i.e "#*" for a bullet nested in a numbered list).
+func listItem(markers, text string) string {
+	var indent strings.Builder
+	for _, marker := range markers[:len(markers)-1] {
+		if marker == '#' {
+			indent.WriteString("   ")
+		} else {
+			indent.WriteString("  ")
+		}
+	}
+
+	if markers[len(markers)-1] == '#' {
+		return indent.String() + "1. " + text
+	}
+	return indent.String() + "- " + text
+}
+
+// tableRow splits a table row into its cells, ignoring
+// pipes within links (i.e [text|url]) and monospace text.
+func tableRow(row string) []string {
+	cells := []string{}
+	var cell strings.Builder
+	depth := 0
+	inCell := false
+
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '[' || strings.HasPrefix(row[i:], "{{"):
+			depth++
+		case (row[i] == ']' || strings.HasPrefix(row[i:], "}}")) && depth > 0:
+			depth--
+		case row[i] == '|' && depth == 0:
+			if inCell {
+				cells = append(cells, wikiInline(strings.TrimSpace(cell.String())))
+				cell.Reset()
+			}
+			// header cells are delimited by ||
+			for i+1 < len(row) && row[i+1] == '|' {
+				i++
+			}
+			inCell = true
+			continue
+		}
+
+		if strings.HasPrefix(row[i:], "{{") || strings.HasPrefix(row[i:], "}}") {
+			cell.WriteString(row[i : i+2])
+			i++
+			continue
+		}
+		cell.WriteByte(row[i])
+	}
+
+	if rest := strings.TrimSpace(cell.String()); rest != "" {
+		cells = append(cells, wikiInline(rest))
+	}
+
+	return cells
+}
+
+// emphasis maps Jira text effect markers
+// to their Markdown equivalents.
+var emphasis = map[string]string{
+	"*":  "**",
+	"_":  "_",
+	"-":  "~~",
+	"??": "_",
+	// Markdown has no underline, superscript or subscript
+	// so the text is rendered without any effect
+	"+": "",
+	"^": "",
+	"~": "",
+}
+
+// wikiInline converts the inline formatting of a line of wiki markup.
+// It walks the line by byte offset, decoding a rune at a time, so that
+// the remainder of the line can be sliced without copying it.
+func wikiInline(text string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		r, size := utf8.DecodeRuneInString(rest)
+
+		switch {
+		case strings.HasPrefix(rest, `\\`):
+			out.WriteString("  \n")
+			i += 2
+			continue
+		case r == '\\' && len(rest) > 1:
+			_, escaped := utf8.DecodeRuneInString(rest[1:])
+			out.WriteString(escape(rest[1 : 1+escaped]))
+			i += 1 + escaped
+			continue
+		case strings.HasPrefix(rest, "{{"):
+			if end := strings.Index(rest[2:], "}}"); end > 0 {
+				out.WriteString(codeSpan(rest[2 : 2+end]))
+				i += 4 + end
+				continue
+			}
+		case r == '{':
+			if end := strings.IndexByte(rest, '}'); end > 0 && macroRegex.MatchString(rest[:end+1]) {
+				i += end + 1
+				continue
+			}
+		case r == '[':
+			if end := strings.IndexByte(rest, ']'); end > 1 {
+				out.WriteString(wikiLink(rest[1:end]))
+				i += end + 1
+				continue
+			}
+		case r == '!':
+			if end := strings.IndexByte(rest[1:], '!'); end > 0 && !strings.ContainsFunc(rest[1:end+1], unicode.IsSpace) {
+				name, _, _ := strings.Cut(rest[1:end+1], "|")
+				out.WriteString("![" + escape(name) + "](" + name + ")")
+				i += end + 2
+				continue
+			}
+		}
+
+		if wrapped, consumed, ok := textEffect(text, i); ok {
+			out.WriteString(wrapped)
+			i += consumed
+			continue
+		}
+
+		out.WriteString(escape(rest[:size]))
+		i += size
+	}
+
+	return out.String()
+}
+
+// textEffect converts a text effect (i.e *bold*) starting at byte
+// offset i of text, returning the converted text and the number of bytes
+// consumed, or false if there is no text effect at offset i.
+func textEffect(text string, i int) (string, int, bool) {
+	marker := text[i : i+1]
+	if marker == "?" {
+		marker = "??"
+	}
+
+	replacement, ok := emphasis[marker]
+	if !ok || !strings.HasPrefix(text[i:], marker) {
+		return "", 0, false
+	}
+
+	// effects must start at a word boundary and
+	// the text within them can't start with whitespace
+	start := i + len(marker)
+	if isWordRune(lastRune(text[:i])) || start >= len(text) || unicode.IsSpace(firstRune(text[start:])) || strings.HasPrefix(text[start:], marker[:1]) {
+		return "", 0, false
+	}
+
+	// markers are ASCII, so can't match within a multi-byte rune
+	for end := start + 1; end < len(text); end++ {
+		if !strings.HasPrefix(text[end:], marker) {
+			continue
+		}
+
+		// effects must end at a word boundary and
+		// the text within them can't end with whitespace
+		after := end + len(marker)
+		if unicode.IsSpace(lastRune(text[:end])) || text[end-1] == marker[0] || isWordRune(firstRune(text[after:])) {
+			continue
+		}
+
+		inner := wikiInline(text[start:end])
+		return replacement + inner + replacement, after - i, true
+	}
+
+	return "", 0, false
+}
+
+// firstRune returns the first rune of text, or utf8.RuneError if it is empty.
+func firstRune(text string) rune {
+	r, _ := utf8.DecodeRuneInString(text)
+	return r
+}
+
+// lastRune returns the last rune of text, or utf8.RuneError if it is empty.
+func lastRune(text string) rune {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return r
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// wikiLink converts the contents of a wiki link (i.e text|url).
+func wikiLink(link string) string {
+	switch {
+	case strings.HasPrefix(link, "~"):
+		return "**@" + escape(strings.TrimPrefix(link, "~")) + "**"
+	case strings.HasPrefix(link, "^"):
+		return "_[" + escape(strings.TrimPrefix(link, "^")) + "]_"
+	case strings.HasPrefix(link, "#"):
+		return escape(strings.TrimPrefix(link, "#"))
+	}
+
+	text, url, ok := strings.Cut(link, "|")
+	if !ok {
+		if strings.Contains(link, "://") || strings.HasPrefix(link, "mailto:") {
+			return "<" + link + ">"
+		}
+		return escape(link)
+	}
+
+	// links may have a tooltip (i.e text|url|tip)
+	url, _, _ = strings.Cut(url, "|")
+	if user, ok := strings.CutPrefix(url, "~"); ok {
+		return "**@" + escape(user) + "**"
+	}
+
+	return "[" + wikiInline(text) + "](" + url + ")"
+}
@@ -1,16 +1,22 @@
 package jira
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	gojira "github.com/andygrunwald/go-jira"
+	"github.com/everettraven/wranglr/pkg/httpcache"
 	"github.com/everettraven/wranglr/pkg/modules"
+	"github.com/everettraven/wranglr/pkg/modules/jira/markdown"
 	"go.starlark.net/starlark"
 )
 
-type Module struct{}
+type Module struct {
+	Cache *httpcache.Cache
+}
 
 func (m *Module) String() string        { return "jira" }
 func (m *Module) Type() string          { return "Module" }
@@ -18,12 +24,21 @@ func (m *Module) Truth() starlark.Bool  { return starlark.False }
 func (m *Module) Freeze()               {}
 func (m *Module) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
 
-const SearchAttr = "search"
+const (
+	SearchAttr      = "search"
+	SearchAsyncAttr = "search_async"
+)
 
 func (m *Module) Attr(name string) (starlark.Value, error) {
 	switch name {
 	case SearchAttr:
-		return starlark.NewBuiltin(SearchAttr, SearchBuiltin()), nil
+		return starlark.NewBuiltin(SearchAttr, SearchBuiltin(m.Cache)), nil
+	case SearchAsyncAttr:
+		return starlark.NewBuiltin(SearchAsyncAttr, SearchAsyncBuiltin(m.Cache)), nil
+	case TransitionAttr:
+		return starlark.NewBuiltin(TransitionAttr, TransitionBuiltin()), nil
+	case AddCommentAttr:
+		return starlark.NewBuiltin(AddCommentAttr, AddCommentBuiltin()), nil
 	default:
 		return nil, fmt.Errorf("unknown attribute %q", name)
 	}
@@ -32,56 +47,109 @@ func (m *Module) Attr(name string) (starlark.Value, error) {
 func (m *Module) AttrNames() []string {
 	return []string{
 		SearchAttr,
+		SearchAsyncAttr,
+		TransitionAttr,
+		AddCommentAttr,
 	}
 }
 
-type searchResult struct {
-	Issues []gojira.Issue `json:"issues,omitempty"`
+func SearchBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.SyncBuiltin(searchFetch(cache))
 }
 
-func SearchBuiltin() modules.BuiltinFunc {
-	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+func SearchAsyncBuiltin(cache *httpcache.Cache) modules.BuiltinFunc {
+	return modules.AsyncBuiltin(searchFetch(cache))
+}
+
+func searchFetch(cache *httpcache.Cache) modules.FetchBuilder {
+	return func(fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (modules.FetchFunc, error) {
 		var host starlark.String
 		var query starlark.String
 		var group starlark.String
+		var limit int
+		var fields *starlark.List
+		var api starlark.String
+
+		var cacheTTL modules.OptionalDuration
 
-		err := starlark.UnpackArgs(SearchAttr, args, kwargs,
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
 			"host", &host,
 			"query", &query,
-			"group?", group,
+			"group?", &group,
+			"limit?", &limit,
+			"fields?", &fields,
+			"api?", &api,
+			"cache_ttl?", &cacheTTL,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		client := NewClient(host.GoString())
+		opts := SearchOptions{
+			Limit: limit,
+			API:   APIAuto,
+		}
 
-		issues, err := client.Issues(query.GoString())
-		if err != nil {
-			return nil, err
+		switch API(api.GoString()) {
+		case "", APIAuto:
+		case APIServer, APICloud:
+			opts.API = API(api.GoString())
+		default:
+			return nil, fmt.Errorf("%s: api must be one of [%s, %s, %s] but was %q", fn.Name(), APIAuto, APIServer, APICloud, api.GoString())
 		}
 
-		return issuesToStarlark(host.GoString(), group.GoString(), issues...), nil
+		if fields != nil {
+			for field := range fields.Elements() {
+				fieldStr, ok := starlark.AsString(field)
+				if !ok {
+					return nil, fmt.Errorf("%s: fields must be a list of strings but contained type %q", fn.Name(), field.Type())
+				}
+				opts.Fields = append(opts.Fields, fieldStr)
+			}
+		}
+
+		return func(ctx context.Context) (starlark.Value, error) {
+			if cacheTTL.Set {
+				ctx = httpcache.WithTTL(ctx, cacheTTL.Duration)
+			}
+
+			client := NewClient(host.GoString(), cache.Client())
+
+			results, err := client.Issues(ctx, opts, query.GoString())
+			if err != nil {
+				return nil, err
+			}
+
+			return modules.NewResults(
+				issuesToStarlark(client, opts.api(client.host), group.GoString(), results.StaleSince, results.Issues...),
+				results.Total,
+				results.Incomplete,
+			), nil
+		}, nil
 	}
 }
 
 type Item struct {
-	issue    gojira.Issue
-	status   string
-	priority int64
-	url      string
-	group    string
-}
+	modules.BaseItem
+	issue Issue
+	url   string
 
-func (i *Item) Priority() int64 {
-	return i.priority
+	// client and api are the client and API flavor used to search
+	// for the item, used for fetching additional data about it on demand.
+	client *Client
+	api    API
 }
 
-func (i *Item) Status() string {
-	if i.status == "" {
-		return "Unknown"
+var _ modules.Commentable = (*Item)(nil)
+
+// NewItem creates an item for an issue returned by the Jira search API,
+// which is browsed at url. Items created this way can't fetch their comments.
+func NewItem(issue Issue, url, group string, staleSince time.Time) *Item {
+	return &Item{
+		BaseItem: modules.NewBaseItem(group, staleSince),
+		issue:    issue,
+		url:      url,
 	}
-	return i.status
 }
 
 func (i *Item) URL() string {
@@ -89,15 +157,140 @@ func (i *Item) URL() string {
 }
 
 func (i *Item) Issue() gojira.Issue {
+	return i.issue.Issue
+}
+
+func (i *Item) Source() string {
+	return "jira"
+}
+
+// ID returns the key of the issue (i.e "PROJ-123").
+func (i *Item) ID() string {
+	return i.issue.Key
+}
+
+func (i *Item) Title() string {
+	return i.issue.Fields.Summary
+}
+
+// Body returns the description of the issue converted to Markdown,
+// from either wiki markup (Jira Server/Data Center) or the
+// Atlassian Document Format (Jira Cloud).
+func (i *Item) Body() string {
+	if doc, ok := i.issue.Documents["description"]; ok {
+		body, err := markdown.FromADF(doc)
+		if err != nil {
+			return documentText(doc)
+		}
+		return body
+	}
+	return markdown.FromWiki(i.issue.Fields.Description)
+}
+
+func (i *Item) Author() string {
+	return userName(i.issue.Fields.Reporter)
+}
+
+func (i *Item) Assignees() []string {
+	if i.issue.Fields.Assignee == nil {
+		return []string{}
+	}
+	return []string{userName(i.issue.Fields.Assignee)}
+}
+
+func (i *Item) Labels() []string {
+	return append([]string{}, i.issue.Fields.Labels...)
+}
+
+func (i *Item) CreatedAt() time.Time {
+	return time.Time(i.issue.Fields.Created)
+}
+
+func (i *Item) UpdatedAt() time.Time {
+	return time.Time(i.issue.Fields.Updated)
+}
+
+// Raw returns the item as returned by the Jira API.
+func (i *Item) Raw() any {
 	return i.issue
 }
 
-func (i *Item) Group() string {
-	if i.group == "" {
-		return "Unknown"
+// Description returns the description of the issue. Descriptions
+// returned in the Atlassian Document Format are converted to plain text.
+func (i *Item) Description() string {
+	if doc, ok := i.issue.Documents["description"]; ok {
+		return documentText(doc)
+	}
+	return i.issue.Fields.Description
+}
+
+// Client returns the client used to fetch the item, which
+// can be used to make further requests about the item.
+func (i *Item) Client() *Client {
+	return i.client
+}
+
+// API returns the flavor of the API used to fetch the item.
+func (i *Item) API() API {
+	return i.api
+}
+
+// Comments fetches the comments on the issue,
+// with their bodies converted to Markdown.
+func (i *Item) Comments(ctx context.Context) (*modules.CommentsResult, error) {
+	// items built with NewItem aren't fetched using a client
+	if i.client == nil {
+		return nil, errors.New("comments are not available for this item")
+	}
+
+	staleness := &httpcache.Staleness{}
+	comments, err := i.client.Comments(ctx, i.api, i.issue.Key, staleness)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &modules.CommentsResult{
+		Comments:   []*modules.Comment{},
+		StaleSince: staleness.Since(),
+	}
+	for _, comment := range comments {
+		body, err := commentBody(comment.Body)
+		if err != nil {
+			return nil, fmt.Errorf("converting comment on %s: %w", i.issue.Key, err)
+		}
+
+		out.Comments = append(out.Comments, &modules.Comment{
+			Author:    userName(comment.Author),
+			Body:      body,
+			CreatedAt: time.Time(comment.Created),
+		})
+	}
+	return out, nil
+}
+
+func commentBody(raw json.RawMessage) (string, error) {
+	if isDocument(raw) {
+		return markdown.FromADF(raw)
+	}
+
+	text := ""
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return "", fmt.Errorf("unmarshalling comment body: %w", err)
 	}
+	return markdown.FromWiki(text), nil
+}
 
-	return i.group
+// userName returns the name of a Jira user. Jira Cloud
+// doesn't return usernames, so the display name is used
+// when there is no username.
+func userName(user *gojira.User) string {
+	if user == nil {
+		return ""
+	}
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.DisplayName
 }
 
 func (i *Item) String() string        { return "todo" }
@@ -107,26 +300,24 @@ func (i *Item) Freeze()               {}
 func (i *Item) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
 
 func (i *Item) Attr(name string) (starlark.Value, error) {
+	if val, err := i.BaseItem.Attr(name); val != nil || err != nil {
+		return val, err
+	}
+
 	switch name {
-	case "status":
-		return starlark.String(i.Status()), nil
-	case "priority":
-		return starlark.MakeInt64(i.Priority()), nil
-	case "group":
-		return starlark.String(i.Group()), nil
 	case "assignee":
 		if i.issue.Fields.Assignee != nil {
-			return starlark.String(i.issue.Fields.Assignee.Name), nil
+			return starlark.String(userName(i.issue.Fields.Assignee)), nil
 		}
 		return starlark.None, nil
 	case "creator":
 		if i.issue.Fields.Creator != nil {
-			return starlark.String(i.issue.Fields.Creator.Name), nil
+			return starlark.String(userName(i.issue.Fields.Creator)), nil
 		}
 		return starlark.None, nil
 	case "reporter":
 		if i.issue.Fields.Reporter != nil {
-			return starlark.String(i.issue.Fields.Reporter.Name), nil
+			return starlark.String(userName(i.issue.Fields.Reporter)), nil
 		}
 		return starlark.None, nil
 	case "type":
@@ -144,15 +335,21 @@ func (i *Item) Attr(name string) (starlark.Value, error) {
 		}
 		return starlark.None, nil
 	case "resolution_date":
-		return starlark.String(time.Time(i.issue.Fields.Resolutiondate).String()), nil
+		return modules.Time(time.Time(i.issue.Fields.Resolutiondate)), nil
 	case "created":
-		return starlark.String(time.Time(i.issue.Fields.Created).String()), nil
+		return modules.Time(time.Time(i.issue.Fields.Created)), nil
+	case modules.FetchCommentsAttr:
+		return modules.FetchCommentsBuiltin(i), nil
 	case "due_date":
-		return starlark.String(time.Time(i.issue.Fields.Duedate).String()), nil
+		return modules.Time(time.Time(i.issue.Fields.Duedate)), nil
 	case "updated":
-		return starlark.String(time.Time(i.issue.Fields.Updated).String()), nil
+		return modules.Time(time.Time(i.issue.Fields.Updated)), nil
+	case modules.AgeAttr:
+		return modules.Since(i.CreatedAt()), nil
+	case modules.SinceUpdateAttr:
+		return modules.Since(i.UpdatedAt()), nil
 	case "description":
-		return starlark.String(i.issue.Fields.Description), nil
+		return starlark.String(i.Description()), nil
 	case "summary":
 		return starlark.String(i.issue.Fields.Summary), nil
 	case "components":
@@ -209,9 +406,7 @@ func (i *Item) Attr(name string) (starlark.Value, error) {
 }
 
 func (i *Item) AttrNames() []string {
-	return []string{
-		"status",
-		"priority",
+	return append(i.BaseItem.AttrNames(),
 		"assignee",
 		"creator",
 		"reporter",
@@ -221,8 +416,11 @@ func (i *Item) AttrNames() []string {
 		"ticket_priority",
 		"resolution_date",
 		"created",
+		modules.FetchCommentsAttr,
 		"due_date",
 		"updated",
+		modules.AgeAttr,
+		modules.SinceUpdateAttr,
 		"description",
 		"summary",
 		"components",
@@ -232,39 +430,22 @@ func (i *Item) AttrNames() []string {
 		"labels",
 		"epic",
 		"sprint",
-	}
+	)
 }
 
+// SetField sets the wranglr-specific field or custom field with the provided name.
 func (i *Item) SetField(name string, val starlark.Value) error {
-	switch name {
-	case "status":
-		i.status = val.String()
-		return nil
-	case "priority":
-		intType, ok := val.(*starlark.Int)
-		if !ok {
-			return fmt.Errorf("priority must be an integer but was attempted to be set to type %q", val.Type())
-		}
-		i64, ok := intType.Int64()
-		if !ok {
-			return errors.New("priority must be a valid int64, but was not")
-		}
-		i.priority = i64
-		return nil
-	default:
-		return fmt.Errorf("cannot set field %q", name)
-	}
+	return i.BaseItem.SetItemField(i, name, val)
 }
 
-func issuesToStarlark(host, group string, issues ...gojira.Issue) starlark.Value {
+func issuesToStarlark(client *Client, api API, group string, staleSince time.Time, issues ...Issue) []starlark.Value {
 	elems := []starlark.Value{}
 	for _, issue := range issues {
-		elems = append(elems, &Item{
-			issue: issue,
-			url:   fmt.Sprintf("%s/browse/%s", host, issue.Key),
-			group: group,
-		})
+		item := NewItem(issue, fmt.Sprintf("%s/browse/%s", client.host, issue.Key), group, staleSince)
+		item.client = client
+		item.api = api
+		elems = append(elems, item)
 	}
 
-	return starlark.NewList(elems)
+	return elems
 }
@@ -0,0 +1,51 @@
+package jira
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gojira "github.com/andygrunwald/go-jira"
+	"go.starlark.net/starlark"
+)
+
+func TestCommentsWithoutClient(t *testing.T) {
+	item := NewItem(Issue{Issue: gojira.Issue{Key: "WR-1"}}, "https://jira.example.com/browse/WR-1", "", time.Time{})
+
+	if _, err := item.Comments(context.Background()); err == nil {
+		t.Fatal("expected an error fetching comments without a client")
+	}
+}
+
+func TestUserAttrs(t *testing.T) {
+	server := &gojira.User{Name: "jdoe", DisplayName: "Jane Doe"}
+	cloud := &gojira.User{AccountID: "5b10a2844c20165700ede21g", DisplayName: "Jane Doe"}
+
+	for _, tc := range []struct {
+		name string
+		user *gojira.User
+		want starlark.Value
+	}{
+		{name: "server", user: server, want: starlark.String("jdoe")},
+		{name: "cloud", user: cloud, want: starlark.String("Jane Doe")},
+		{name: "unset", want: starlark.None},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			item := NewItem(Issue{Issue: gojira.Issue{Fields: &gojira.IssueFields{
+				Assignee: tc.user,
+				Creator:  tc.user,
+				Reporter: tc.user,
+			}}}, "", "", time.Time{})
+
+			for _, attr := range []string{"assignee", "creator", "reporter"} {
+				got, err := item.Attr(attr)
+				if err != nil {
+					t.Fatalf("getting %s: %v", attr, err)
+				}
+				if got != tc.want {
+					t.Errorf("got %s %v, want %v", attr, got, tc.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package modules
+
+import (
+	"context"
+	"sync"
+
+	"go.starlark.net/starlark"
+)
+
+const planLocal = "wranglr.plan"
+
+// Change is a change to an item at its source requested
+// by a mutating builtin (i.e github.add_labels(...)).
+type Change struct {
+	Item Item
+
+	// Description describes the change (i.e `add labels ["bug"]`).
+	Description string
+
+	apply func(ctx context.Context) error
+}
+
+func NewChange(item Item, description string, apply func(ctx context.Context) error) *Change {
+	return &Change{
+		Item:        item,
+		Description: description,
+		apply:       apply,
+	}
+}
+
+// Apply makes the change at the source of the item.
+func (c *Change) Apply(ctx context.Context) error {
+	return c.apply(ctx)
+}
+
+// Plan collects the changes requested while executing a configuration
+// so that they can be reviewed before any of them are applied.
+// It is safe for concurrent use.
+type Plan struct {
+	mu      sync.Mutex
+	changes []*Change
+}
+
+func (p *Plan) Add(change *Change) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.changes = append(p.changes, change)
+}
+
+// Changes returns the changes in the order they were requested.
+func (p *Plan) Changes() []*Change {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]*Change{}, p.changes...)
+}
+
+// SetPlan sets the plan that mutating builtins
+// executed by the thread add their changes to.
+func SetPlan(thread *starlark.Thread, plan *Plan) {
+	thread.SetLocal(planLocal, plan)
+}
+
+// PlanFor returns the plan that mutating builtins executed by the
+// thread add their changes to. If no plan has been set on the
+// thread, an empty plan is set and returned.
+func PlanFor(thread *starlark.Thread) *Plan {
+	if plan, ok := thread.Local(planLocal).(*Plan); ok {
+		return plan
+	}
+
+	plan := &Plan{}
+	SetPlan(thread, plan)
+	return plan
+}
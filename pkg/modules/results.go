@@ -0,0 +1,130 @@
+package modules
+
+import (
+	"fmt"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// Results is a list of items returned by a search that
+// also carries metadata about the search that produced it.
+// It behaves like a regular Starlark list and adding it to
+// another list (or Results) produces a regular Starlark list.
+type Results struct {
+	*starlark.List
+	totalCount int
+	incomplete bool
+
+	// staleSince is the time the oldest of the results was last successfully
+	// fetched if any were served from the cache because the source couldn't be
+	// reached or wranglr is running offline, or the zero time otherwise.
+	staleSince time.Time
+}
+
+func NewResults(items []starlark.Value, totalCount int, incomplete bool) *Results {
+	return &Results{
+		List:       starlark.NewList(items),
+		totalCount: totalCount,
+		incomplete: incomplete,
+	}
+}
+
+// TotalCount returns the total number of results the
+// source reported, which may be more than were returned.
+func (r *Results) TotalCount() int {
+	return r.totalCount
+}
+
+// Incomplete returns whether the results were truncated.
+func (r *Results) Incomplete() bool {
+	return r.incomplete
+}
+
+// StaleSince returns the time the oldest of the results was
+// last successfully fetched if any of them are stale.
+func (r *Results) StaleSince() time.Time {
+	return r.staleSince
+}
+
+// SetStaleSince sets the time the oldest of the results was last
+// successfully fetched, for results that are values rather than items,
+// which can't record whether they are stale themselves.
+func (r *Results) SetStaleSince(staleSince time.Time) {
+	r.staleSince = staleSince
+}
+
+const (
+	TotalCountAttr        = "total_count"
+	IncompleteResultsAttr = "incomplete_results"
+)
+
+func (r *Results) Type() string { return "search_results" }
+
+func (r *Results) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case TotalCountAttr:
+		return starlark.MakeInt(r.totalCount), nil
+	case IncompleteResultsAttr:
+		return starlark.Bool(r.incomplete), nil
+	default:
+		return r.List.Attr(name)
+	}
+}
+
+func (r *Results) AttrNames() []string {
+	return append([]string{TotalCountAttr, IncompleteResultsAttr}, r.List.AttrNames()...)
+}
+
+func (r *Results) CompareSameType(op syntax.Token, y starlark.Value, depth int) (bool, error) {
+	return starlark.CompareDepth(op, r.List, y.(*Results).List, depth)
+}
+
+func (r *Results) Binary(op syntax.Token, y starlark.Value, side starlark.Side) (starlark.Value, error) {
+	switch op {
+	case syntax.PLUS:
+		var other *starlark.List
+		switch list := y.(type) {
+		case *starlark.List:
+			other = list
+		case *Results:
+			other = list.List
+		default:
+			return nil, nil
+		}
+
+		elems := []starlark.Value{}
+		if side == starlark.Right {
+			elems = append(elems, listElements(other)...)
+			elems = append(elems, listElements(r.List)...)
+		} else {
+			elems = append(elems, listElements(r.List)...)
+			elems = append(elems, listElements(other)...)
+		}
+
+		return starlark.NewList(elems), nil
+	case syntax.IN, syntax.NOT_IN:
+		if side != starlark.Right {
+			return nil, nil
+		}
+
+		found, err := starlark.Binary(op, y, r.List)
+		if err != nil {
+			return nil, fmt.Errorf("checking membership: %w", err)
+		}
+
+		return found, nil
+	default:
+		return nil, nil
+	}
+}
+
+func listElements(list *starlark.List) []starlark.Value {
+	elems := []starlark.Value{}
+	for elem := range list.Elements() {
+		elems = append(elems, elem)
+	}
+
+	return elems
+}
@@ -0,0 +1,81 @@
+package modules
+
+import (
+	"time"
+
+	"go.starlark.net/starlark"
+)
+
+const stateLocal = "wranglr.state"
+
+// ItemState records local state about items that
+// is kept between runs, such as which items have been seen.
+type ItemState interface {
+	// Seen returns whether the item with the URL has
+	// been seen since it was last updated at updatedAt.
+	Seen(url string, updatedAt time.Time) bool
+
+	// SnoozedUntil returns whether the item with the URL, which was last
+	// updated at updatedAt, is snoozed and when the snooze ends. The time is zero
+	// if the snooze ends when the item is updated or the item was dismissed.
+	SnoozedUntil(url string, updatedAt time.Time) (time.Time, bool)
+}
+
+// SetItemState sets the state used to determine whether the items
+// fetched by builtins executed by the thread have been seen or snoozed.
+func SetItemState(thread *starlark.Thread, state ItemState) {
+	thread.SetLocal(stateLocal, state)
+}
+
+// itemState returns the ItemState of the thread, or nil if none has been set.
+func itemState(thread *starlark.Thread) ItemState {
+	state, _ := thread.Local(stateLocal).(ItemState)
+	return state
+}
+
+// applyState sets whether each item in the value returned by a fetch
+// has been seen or snoozed. Values other than lists are left as-is.
+func applyState(state ItemState, value starlark.Value) {
+	if state == nil {
+		return
+	}
+
+	var list *starlark.List
+	switch v := value.(type) {
+	case *Results:
+		list = v.List
+	case *starlark.List:
+		list = v
+	default:
+		return
+	}
+
+	for elem := range list.Elements() {
+		setState(state, elem)
+	}
+}
+
+// SetState sets whether the item has been seen or snoozed
+// using the ItemState of the thread, if one has been set.
+func SetState(thread *starlark.Thread, item Item) {
+	if state := itemState(thread); state != nil {
+		setState(state, item)
+	}
+}
+
+// setState sets whether the value, if it is an item, has been seen or
+// snoozed. Items without a URL can't be tracked, so are left as-is.
+func setState(state ItemState, value starlark.Value) {
+	item, ok := value.(interface {
+		Item
+		SetSeen(seen bool)
+		SetSnoozed(snoozed bool, until time.Time)
+	})
+	if !ok || item.URL() == "" {
+		return
+	}
+
+	item.SetSeen(state.Seen(item.URL(), item.UpdatedAt()))
+	until, snoozed := state.SnoozedUntil(item.URL(), item.UpdatedAt())
+	item.SetSnoozed(snoozed, until)
+}
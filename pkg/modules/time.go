@@ -0,0 +1,42 @@
+package modules
+
+import (
+	"time"
+
+	startime "go.starlark.net/lib/time"
+	"go.starlark.net/starlark"
+)
+
+const (
+	// AgeAttr is the attribute for the time since an item was created.
+	AgeAttr = "age"
+
+	// SinceUpdateAttr is the attribute for the time since an item was last updated.
+	SinceUpdateAttr = "since_update"
+)
+
+// Time converts t to a Starlark time, or None if t is the zero time.
+func Time(t time.Time) starlark.Value {
+	if t.IsZero() {
+		return starlark.None
+	}
+	return startime.Time(t)
+}
+
+// OptionalTime converts t to a Starlark time,
+// or None if t is nil or the zero time.
+func OptionalTime(t *time.Time) starlark.Value {
+	if t == nil {
+		return starlark.None
+	}
+	return Time(*t)
+}
+
+// Since returns the time elapsed since t as a Starlark
+// duration, or None if t is the zero time.
+func Since(t time.Time) starlark.Value {
+	if t.IsZero() {
+		return starlark.None
+	}
+	return startime.Duration(time.Since(t))
+}
@@ -0,0 +1,41 @@
+package modules
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// CABundleTransport returns an http.RoundTripper that trusts the
+// certificate authorities in the PEM encoded bundle at path in addition
+// to the system certificate authorities. It returns http.DefaultTransport
+// if path is empty.
+func CABundleTransport(path string) (http.RoundTripper, error) {
+	if path == "" {
+		return http.DefaultTransport, nil
+	}
+
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle %q does not contain any PEM encoded certificates", path)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	return transport, nil
+}
@@ -0,0 +1,80 @@
+package modules
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeCABundle writes the self-signed certificate of the
+// server to a PEM encoded CA bundle and returns its path.
+func writeCABundle(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, bundle, 0o600); err != nil {
+		t.Fatalf("writing CA bundle: %v", err)
+	}
+	return path
+}
+
+func TestCABundleTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	t.Run("trusts the CA bundle", func(t *testing.T) {
+		transport, err := CABundleTransport(writeCABundle(t, server))
+		if err != nil {
+			t.Fatalf("creating transport: %v", err)
+		}
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+	})
+
+	t.Run("fails without the CA bundle", func(t *testing.T) {
+		transport, err := CABundleTransport("")
+		if err != nil {
+			t.Fatalf("creating transport: %v", err)
+		}
+		if transport != http.DefaultTransport {
+			t.Errorf("expected the default transport without a CA bundle")
+		}
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err == nil {
+			_ = resp.Body.Close()
+			t.Fatal("expected the self-signed certificate to be untrusted")
+		}
+	})
+
+	t.Run("rejects bundles without certificates", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "empty.pem")
+		if err := os.WriteFile(path, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatalf("writing CA bundle: %v", err)
+		}
+
+		if _, err := CABundleTransport(path); err == nil {
+			t.Error("expected an error for a bundle without certificates")
+		}
+	})
+
+	t.Run("rejects missing bundles", func(t *testing.T) {
+		if _, err := CABundleTransport(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+			t.Error("expected an error for a missing bundle")
+		}
+	})
+}
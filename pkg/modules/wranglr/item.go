@@ -0,0 +1,186 @@
+package wranglr
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/modules"
+	"go.starlark.net/starlark"
+)
+
+// Item is an item created by the configuration using wranglr.item(...),
+// for things that don't come from a source module (i.e a reminder or
+// a row from a CSV file).
+type Item struct {
+	modules.BaseItem
+	record itemRecord
+}
+
+// itemRecord holds the fields an item was created with,
+// which is its representation in JSON output.
+type itemRecord struct {
+	Title  string   `json:"title"`
+	URL    string   `json:"url,omitempty"`
+	Body   string   `json:"body,omitempty"`
+	Labels []string `json:"labels"`
+}
+
+var _ modules.Item = (*Item)(nil)
+
+func (i *Item) URL() string {
+	return i.record.URL
+}
+
+func (i *Item) Source() string {
+	return "wranglr"
+}
+
+// ID returns the URL of the item, or its
+// title if the item doesn't have a URL.
+func (i *Item) ID() string {
+	if i.record.URL == "" {
+		return i.record.Title
+	}
+	return i.record.URL
+}
+
+func (i *Item) Title() string {
+	return i.record.Title
+}
+
+func (i *Item) Body() string {
+	return i.record.Body
+}
+
+func (i *Item) Author() string {
+	return ""
+}
+
+func (i *Item) Assignees() []string {
+	return []string{}
+}
+
+func (i *Item) Labels() []string {
+	return append([]string{}, i.record.Labels...)
+}
+
+func (i *Item) CreatedAt() time.Time {
+	return time.Time{}
+}
+
+func (i *Item) UpdatedAt() time.Time {
+	return time.Time{}
+}
+
+// Raw returns the fields the item was created with.
+func (i *Item) Raw() any {
+	return i.record
+}
+
+func (i *Item) String() string        { return i.Source() + " " + i.ID() }
+func (i *Item) Type() string          { return fmt.Sprintf("%T", i) }
+func (i *Item) Truth() starlark.Bool  { return starlark.True }
+func (i *Item) Freeze()               {}
+func (i *Item) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
+
+func (i *Item) Attr(name string) (starlark.Value, error) {
+	if val, err := i.BaseItem.Attr(name); val != nil || err != nil {
+		return val, err
+	}
+
+	switch name {
+	case "title":
+		return starlark.String(i.record.Title), nil
+	case "url":
+		return starlark.String(i.record.URL), nil
+	case "body":
+		return starlark.String(i.record.Body), nil
+	case "labels":
+		return modules.StringList(i.record.Labels), nil
+	default:
+		return nil, fmt.Errorf("unknown attribute %q", name)
+	}
+}
+
+func (i *Item) AttrNames() []string {
+	return append(i.BaseItem.AttrNames(),
+		"title",
+		"url",
+		"body",
+		"labels",
+	)
+}
+
+// SetField sets the wranglr-specific field or custom field with the provided name.
+func (i *Item) SetField(name string, val starlark.Value) error {
+	return i.BaseItem.SetItemField(i, name, val)
+}
+
+// ItemBuiltin returns a builtin that creates an item from the provided
+// fields, which can be rendered like the items returned by source modules.
+func ItemBuiltin() modules.BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var title, url, body string
+		var status, priority, group starlark.Value
+		var labels *starlark.List
+		var fields *starlark.Dict
+		err := starlark.UnpackArgs(fn.Name(), args, kwargs,
+			"title", &title,
+			"url?", &url,
+			"body?", &body,
+			"status?", &status,
+			"priority?", &priority,
+			"group?", &group,
+			"labels?", &labels,
+			"fields?", &fields,
+		)
+		if err != nil {
+			return starlark.None, err
+		}
+
+		labelStrs, err := modules.AsStrings(labels)
+		if err != nil {
+			return starlark.None, fmt.Errorf("wranglr.item(): labels %w", err)
+		}
+
+		item := &Item{
+			record: itemRecord{
+				Title:  title,
+				URL:    url,
+				Body:   body,
+				Labels: labelStrs,
+			},
+		}
+
+		for _, field := range []struct {
+			name string
+			val  starlark.Value
+		}{
+			{modules.StatusAttr, status},
+			{modules.PriorityAttr, priority},
+			{modules.GroupAttr, group},
+		} {
+			if field.val == nil {
+				continue
+			}
+			if err := item.SetField(field.name, field.val); err != nil {
+				return starlark.None, fmt.Errorf("wranglr.item(): %w", err)
+			}
+		}
+
+		if fields != nil {
+			for key, val := range fields.Entries() {
+				name, ok := key.(starlark.String)
+				if !ok {
+					return starlark.None, fmt.Errorf("wranglr.item(): fields must have string keys, but contained a key of type %q", key.Type())
+				}
+				if err := item.SetField(string(name), val); err != nil {
+					return starlark.None, fmt.Errorf("wranglr.item(): %w", err)
+				}
+			}
+		}
+
+		modules.SetState(thread, item)
+		return item, nil
+	}
+}
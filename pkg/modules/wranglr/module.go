@@ -2,16 +2,16 @@ package wranglr
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/everettraven/wranglr/pkg/modules"
-	"github.com/everettraven/wranglr/pkg/modules/github"
-	"github.com/everettraven/wranglr/pkg/modules/jira"
 	"github.com/everettraven/wranglr/pkg/printers"
 	"go.starlark.net/starlark"
 )
 
 type Module struct {
-	Output string
+	Printer printers.Printer
+	Options Options
 }
 
 func (m *Module) String() string        { return "wranglr" }
@@ -20,12 +20,20 @@ func (m *Module) Truth() starlark.Bool  { return starlark.False }
 func (m *Module) Freeze()               {}
 func (m *Module) Hash() (uint32, error) { return 0, fmt.Errorf("hashing not yet implemented") }
 
-const RenderAttr = "render"
+const (
+	RenderAttr = "render"
+	WaitAttr   = "wait"
+	ItemAttr   = "item"
+)
 
 func (m *Module) Attr(name string) (starlark.Value, error) {
 	switch name {
 	case RenderAttr:
-		return starlark.NewBuiltin(RenderAttr, RenderBuiltin(m.Output)), nil
+		return starlark.NewBuiltin(RenderAttr, RenderBuiltin(m.Printer, m.Options)), nil
+	case WaitAttr:
+		return starlark.NewBuiltin(WaitAttr, WaitBuiltin()), nil
+	case ItemAttr:
+		return starlark.NewBuiltin(ItemAttr, ItemBuiltin()), nil
 	default:
 		return nil, fmt.Errorf("unknown attribute %q", name)
 	}
@@ -34,48 +42,87 @@ func (m *Module) Attr(name string) (starlark.Value, error) {
 func (m *Module) AttrNames() []string {
 	return []string{
 		RenderAttr,
+		WaitAttr,
+		ItemAttr,
 	}
 }
 
-func RenderBuiltin(output string) modules.BuiltinFunc {
+func RenderBuiltin(printer printers.Printer, opts Options) modules.BuiltinFunc {
 	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-		values := []starlark.Value{}
+		items := []modules.Item{}
 		for i, arg := range args {
-			list, ok := arg.(*starlark.List)
-			if !ok {
-				return starlark.None, fmt.Errorf("wranglr.render(): positional arguments must be lists, but positional argument %d was type %s", i, arg.Type())
+			list, err := asItems(arg)
+			if err != nil {
+				return starlark.None, fmt.Errorf("wranglr.render(): positional argument %d %w", i, err)
 			}
 
-			for elem := range list.Elements() {
-				switch elem.(type) {
-				case *github.Item, *jira.Item:
-					// do nothing, valid case
-				default:
-					return starlark.None, fmt.Errorf("wranglr.render(): positional arguments must be lists of supported types, but positional argument %d contains unsupported elements", i)
-				}
+			items = append(items, list...)
+		}
+
+		if !opts.ShowSnoozed {
+			items = slices.DeleteFunc(items, modules.Item.Snoozed)
+		}
 
-				values = append(values, elem)
-			}
+		err := printer.Print(items...)
+		if err != nil {
+			return starlark.None, err
 		}
 
-		// TODO: a printer registry, or should each value be responsible for implementing an output interface?
-		switch output {
-		case "json":
-			p := printers.JSON{}
-			err := p.Print(values...)
-			if err != nil {
-				return starlark.None, err
+		return starlark.None, nil
+	}
+}
+
+// asItems returns the items in value, which
+// must be a list of items or search results.
+func asItems(value starlark.Value) ([]modules.Item, error) {
+	var list *starlark.List
+	switch v := value.(type) {
+	case *starlark.List:
+		list = v
+	case *modules.Results:
+		list = v.List
+	default:
+		return nil, fmt.Errorf("must be a list, but was type %s", value.Type())
+	}
+
+	items := []modules.Item{}
+	for elem := range list.Elements() {
+		item, ok := elem.(modules.Item)
+		if !ok {
+			return nil, fmt.Errorf("must be a list of items, but contains type %s", elem.Type())
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func WaitBuiltin() modules.BuiltinFunc {
+	return func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		if len(kwargs) > 0 {
+			return starlark.None, fmt.Errorf("wranglr.wait(): unexpected keyword arguments")
+		}
+
+		futures := []*modules.Future{}
+		for i, arg := range args {
+			future, ok := arg.(*modules.Future)
+			if !ok {
+				return starlark.None, fmt.Errorf("wranglr.wait(): positional arguments must be futures, but positional argument %d was type %s", i, arg.Type())
 			}
-		case "interactive":
-			p := printers.Interactive{}
-			err := p.Print(values...)
+			futures = append(futures, future)
+		}
+
+		ctx := modules.Context(thread)
+		results := starlark.Tuple{}
+		for _, future := range futures {
+			result, err := future.Wait(ctx)
 			if err != nil {
-				return starlark.None, err
+				return starlark.None, fmt.Errorf("wranglr.wait(): %w", err)
 			}
-		default:
-			return starlark.None, fmt.Errorf("unknown output format %q", output)
+			results = append(results, result)
 		}
 
-		return starlark.None, nil
+		return results, nil
 	}
 }
@@ -2,8 +2,17 @@ package wranglr
 
 import (
 	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/printers"
 )
 
-func New(output string) (string, starlark.Value) {
-	return "wranglr", &Module{Output: output}
+// Options configures the wranglr module.
+type Options struct {
+	// ShowSnoozed renders items that were snoozed or
+	// dismissed, which wranglr.render(...) skips by default.
+	ShowSnoozed bool
+}
+
+func New(printer printers.Printer, opts Options) (string, starlark.Value) {
+	return "wranglr", &Module{Printer: printer, Options: opts}
 }
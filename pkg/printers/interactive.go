@@ -1,27 +1,81 @@
 package printers
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/everettraven/wranglr/pkg/modules/github"
-	"github.com/everettraven/wranglr/pkg/modules/jira"
+	"github.com/everettraven/wranglr/pkg/modules"
 	"github.com/everettraven/wranglr/pkg/printers/interactive"
 	"github.com/everettraven/wranglr/pkg/printers/interactive/interactables"
-	"go.starlark.net/starlark"
 )
 
-type Interactive struct{}
+type Interactive struct {
+	ctx      context.Context
+	refresh  Refresher
+	interval time.Duration
+	tracker  Tracker
 
-func (i *Interactive) Print(results ...starlark.Value) error {
-	interactableResults := []interactive.Interactable{}
-	for _, result := range results {
-		switch item := result.(type) {
-		case *jira.Item:
-			interactableResults = append(interactableResults, interactables.NewJira(item))
-		case *github.Item:
-			interactableResults = append(interactableResults, interactables.NewGitHub(item))
-		}
+	// showSnoozed is whether items that are
+	// snoozed while being shown are still shown.
+	showSnoozed bool
+
+	// renders is the number of times Print has been called, which is
+	// used to find the items that a refresh renders in place of the
+	// items being printed.
+	renders int
+}
+
+func (i *Interactive) SetRefresher(refresh Refresher, interval time.Duration) {
+	i.refresh = refresh
+	i.interval = interval
+}
+
+func (i *Interactive) SetContext(ctx context.Context) {
+	i.ctx = ctx
+}
+
+func (i *Interactive) SetTracker(tracker Tracker, showSnoozed bool) {
+	i.tracker = tracker
+	i.showSnoozed = showSnoozed
+}
+
+func (i *Interactive) Print(items ...modules.Item) error {
+	ctx := i.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// anything still running in the background when the
+	// view is closed, such as a refresh, is no longer needed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	r := interactive.NewRoot(newInteractables(items...)...)
+	r.SetContext(ctx)
+
+	render := i.renders
+	i.renders++
+
+	if i.tracker != nil {
+		r.SetTracker(i.tracker, i.showSnoozed)
+	}
+
+	if i.refresh != nil {
+		r.SetRefresh(func(ctx context.Context) ([]interactive.Interactable, []string, error) {
+			rendered, warnings, err := i.refresh(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if render >= len(rendered) {
+				return nil, nil, fmt.Errorf("the configuration rendered items %d time(s) when refreshed, but these items were rendered by call %d", len(rendered), render+1)
+			}
+
+			return newInteractables(rendered[render]...), warnings, nil
+		}, i.interval)
 	}
-	r := interactive.NewRoot(interactableResults...)
 
 	p := tea.NewProgram(r, tea.WithAltScreen())
 
@@ -32,3 +86,11 @@ func (i *Interactive) Print(results ...starlark.Value) error {
 
 	return nil
 }
+
+func newInteractables(items ...modules.Item) []interactive.Interactable {
+	interactableResults := []interactive.Interactable{}
+	for _, item := range items {
+		interactableResults = append(interactableResults, interactables.New(item))
+	}
+	return interactableResults
+}
@@ -0,0 +1,146 @@
+package interactive
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Searchable is implemented by interactables
+// that can be found using the filter prompt.
+type Searchable interface {
+	SearchFields() SearchFields
+}
+
+// SearchFields are the fields of an item that filters are matched against.
+type SearchFields struct {
+	ID        string
+	Title     string
+	Author    string
+	Body      string
+	Labels    []string
+	Assignees []string
+}
+
+// query is a parsed filter, such as `label:bug author:alice webhooks`.
+type query struct {
+	// terms are fuzzy matched against the ID, title,
+	// author and labels, or found within the body.
+	terms []string
+
+	// qualifiers are found within the field they qualify.
+	labels    []string
+	authors   []string
+	assignees []string
+}
+
+func parseQuery(text string) query {
+	q := query{}
+	for _, token := range tokenize(strings.ToLower(text)) {
+		qualifier, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			q.terms = append(q.terms, token)
+			continue
+		}
+
+		switch qualifier {
+		case "label":
+			q.labels = append(q.labels, value)
+		case "author":
+			q.authors = append(q.authors, value)
+		case "assignee":
+			q.assignees = append(q.assignees, value)
+		default:
+			q.terms = append(q.terms, token)
+		}
+	}
+	return q
+}
+
+// tokenize splits text on whitespace, keeping text within double
+// quotes together so that values may contain spaces (i.e `label:"needs info"`).
+func tokenize(text string) []string {
+	tokens := []string{}
+	var token strings.Builder
+	quoted := false
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if token.Len() > 0 {
+				tokens = append(tokens, token.String())
+				token.Reset()
+			}
+		default:
+			token.WriteRune(r)
+		}
+	}
+
+	if token.Len() > 0 {
+		tokens = append(tokens, token.String())
+	}
+	return tokens
+}
+
+func (q query) matches(fields SearchFields) bool {
+	for _, label := range q.labels {
+		if !containsAny(fields.Labels, label) {
+			return false
+		}
+	}
+
+	for _, author := range q.authors {
+		if !strings.Contains(strings.ToLower(fields.Author), author) {
+			return false
+		}
+	}
+
+	for _, assignee := range q.assignees {
+		if !containsAny(fields.Assignees, assignee) {
+			return false
+		}
+	}
+
+	for _, term := range q.terms {
+		matched := fuzzy(fields.ID, term) ||
+			fuzzy(fields.Title, term) ||
+			fuzzy(fields.Author, term) ||
+			strings.Contains(strings.ToLower(fields.Body), term)
+
+		for _, label := range fields.Labels {
+			matched = matched || fuzzy(label, term)
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsAny returns whether any of the values contain the
+// provided lowercase substring, ignoring the case of the values.
+func containsAny(values []string, substr string) bool {
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzy returns whether the characters of the provided lowercase
+// pattern appear in order within the text, ignoring the case of the
+// text. For example, "wbhk" matches "Support webhooks".
+func fuzzy(text, pattern string) bool {
+	text = strings.ToLower(text)
+	for _, r := range pattern {
+		i := strings.IndexRune(text, r)
+		if i < 0 {
+			return false
+		}
+		text = text[i+len(string(r)):]
+	}
+	return true
+}
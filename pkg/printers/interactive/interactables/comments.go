@@ -0,0 +1,90 @@
+package interactables
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+// commentsPane shows the comments on an item, which
+// are loaded the first time the pane is shown. It is embedded
+// by the interactables of items that support comments, so that
+// only their pages implement pageset.Commentable.
+type commentsPane struct {
+	item     modules.Commentable
+	visible  bool
+	loading  bool
+	comments *modules.CommentsResult
+	err      error
+}
+
+// ToggleComments shows or hides the comments, returning a command that
+// loads them using ctx if they haven't been successfully loaded yet.
+func (c *commentsPane) ToggleComments(ctx context.Context) tea.Cmd {
+	c.visible = !c.visible
+	if !c.visible || c.loading || c.comments != nil {
+		return nil
+	}
+
+	c.loading = true
+	c.err = nil
+
+	item := c.item
+	return func() tea.Msg {
+		result, err := item.Comments(ctx)
+		return commentsLoadedMsg{pane: c, comments: result, err: err}
+	}
+}
+
+type commentsLoadedMsg struct {
+	pane     *commentsPane
+	comments *modules.CommentsResult
+	err      error
+}
+
+func (m commentsLoadedMsg) Update() {
+	m.pane.loading = false
+	m.pane.comments = m.comments
+	m.pane.err = m.err
+}
+
+func (c *commentsPane) Render(width int) string {
+	if !c.visible {
+		return ""
+	}
+
+	var out strings.Builder
+	out.WriteString(titleStyle.Render("Comments") + "\n\n")
+
+	switch {
+	case c.loading:
+		return out.String() + projectStyle.Render("loading comments...") + "\n"
+	case c.err != nil:
+		return out.String() + stateClosedStyle.Width(width).Render(fmt.Sprintf("failed to load comments: %v", c.err)) + "\n"
+	}
+
+	// comments served from the cache are marked
+	// the same way as the item they're on
+	out.WriteString(renderStale(c.comments.StaleSince))
+
+	if len(c.comments.Comments) == 0 {
+		out.WriteString(projectStyle.Render("no comments") + "\n")
+	}
+
+	for _, comment := range c.comments.Comments {
+		out.WriteString(fmt.Sprintf(
+			"%s %s\n",
+			titleStyle.Render(comment.Author),
+			projectStyle.Render(comment.CreatedAt.Local().Format("2006-01-02 15:04")),
+		))
+
+		bodyOut, _ := glamour.Render(comment.Body, "dark")
+		out.WriteString(bodyOut)
+	}
+
+	return out.String()
+}
@@ -0,0 +1,34 @@
+package interactables
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"go.starlark.net/starlark"
+)
+
+// renderCustomFields renders the custom fields set on the item by the
+// configuration, or an empty string if the item has no custom fields.
+func renderCustomFields(fields map[string]starlark.Value, width int) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	out.WriteString(titleStyle.Render("Custom Fields") + "\n\n")
+
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		// strings are shown without quotes
+		value, ok := starlark.AsString(fields[name])
+		if !ok {
+			value = fields[name].String()
+		}
+
+		out.WriteString(lipgloss.NewStyle().Width(width).Render(projectStyle.Render(name)+" "+value) + "\n")
+	}
+
+	return out.String() + "\n"
+}
@@ -0,0 +1,156 @@
+package interactables
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/everettraven/wranglr/pkg/modules"
+	"github.com/everettraven/wranglr/pkg/printers/interactive"
+	"github.com/everettraven/wranglr/pkg/printers/interactive/interactables/linkopener"
+	"github.com/everettraven/wranglr/pkg/printers/interactive/models/pageset"
+)
+
+// glyphs indicating the status of CI checks.
+const (
+	checkPassed  = ""
+	checkFailed  = ""
+	checkPending = ""
+)
+
+// base implements the parts of an interactable
+// that are common to all items.
+type base struct {
+	item modules.Item
+}
+
+func (b base) Priority() int64 {
+	return b.item.Priority()
+}
+
+func (b base) Status() string {
+	return b.item.Status()
+}
+
+func (b base) Group() string {
+	return b.item.Group()
+}
+
+func (b base) Open() tea.Cmd {
+	// items created by the configuration may not have a URL
+	if b.item.URL() == "" {
+		return nil
+	}
+
+	cmd := linkopener.New(b.item.URL()).Open()
+	return tea.ExecProcess(cmd, nil)
+}
+
+func (b base) SearchFields() interactive.SearchFields {
+	return interactive.SearchFields{
+		ID:        b.item.ID(),
+		Title:     b.item.Title(),
+		Author:    b.item.Author(),
+		Body:      b.item.Body(),
+		Labels:    b.item.Labels(),
+		Assignees: b.item.Assignees(),
+	}
+}
+
+func (b base) Identity() string {
+	return b.item.Source() + " " + b.item.ID()
+}
+
+func (b base) URL() string {
+	return b.item.URL()
+}
+
+func (b base) Revision() time.Time {
+	return b.item.UpdatedAt()
+}
+
+func (b base) Row() pageset.Row {
+	return pageset.Row{
+		ID:        b.item.ID(),
+		Title:     b.item.Title(),
+		Assignees: b.item.Assignees(),
+		CreatedAt: b.item.CreatedAt(),
+		Priority:  b.item.Priority(),
+		Status:    b.item.Status(),
+	}
+}
+
+// Generic renders any item using only the
+// fields common to all items.
+type Generic struct {
+	base
+}
+
+func NewGeneric(item modules.Item) *Generic {
+	return &Generic{
+		base: base{item: item},
+	}
+}
+
+func (g *Generic) Render(width int) string {
+	var out strings.Builder
+
+	out.WriteString(renderStale(g.item.StaleSince()))
+
+	out.WriteString(projectStyle.Render(fmt.Sprintf("%s  %s", g.item.Source(), g.item.ID())) + "\n\n")
+	out.WriteString(titleStyle.Width(width).Render(g.item.Title()) + "\n\n")
+
+	if author := g.item.Author(); author != "" {
+		out.WriteString(fmt.Sprintf(
+			"%s %s",
+			projectStyle.Render("by"),
+			titleStyle.Render(author),
+		))
+		out.WriteString("\n\n")
+	}
+
+	out.WriteString(renderAssignees(g.item.Assignees()))
+	out.WriteString("\n\n")
+
+	labelsStr := ""
+	for _, label := range g.item.Labels() {
+		labelsStr += labelStyle.Background(lipgloss.Cyan).Render(label) + " "
+	}
+	if len(labelsStr) > 0 {
+		out.WriteString(lipgloss.NewStyle().Width(width).Render(labelsStr))
+		out.WriteString("\n")
+	}
+
+	bodyOut, _ := glamour.Render(g.item.Body(), "dark")
+	out.WriteString(bodyOut)
+	out.WriteString(renderCustomFields(g.item.CustomFields(), width))
+
+	return lipgloss.NewStyle().MarginLeft(2).Render(out.String())
+}
+
+func renderAssignees(assignees []string) string {
+	if len(assignees) == 0 {
+		return " " + projectStyle.Render("unassigned")
+	}
+
+	var out strings.Builder
+	out.WriteString(" ")
+	for _, assignee := range assignees {
+		out.WriteString(titleStyle.Render(assignee + " "))
+	}
+	return out.String()
+}
+
+// splitList splits a comma separated list, ignoring empty elements.
+func splitList(list string) []string {
+	elems := []string{}
+	for _, elem := range strings.Split(list, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
@@ -1,15 +1,16 @@
 package interactables
 
 import (
+	"context"
 	"fmt"
-	"regexp"
 	"strings"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/everettraven/wranglr/pkg/modules"
 	"github.com/everettraven/wranglr/pkg/modules/github"
-	"github.com/everettraven/wranglr/pkg/printers/interactive/interactables/linkopener"
+	"github.com/everettraven/wranglr/pkg/printers/interactive"
+	"github.com/everettraven/wranglr/pkg/printers/interactive/models/pageset"
 )
 
 const (
@@ -20,59 +21,139 @@ const (
 	prMerged    = ""
 )
 
+func init() {
+	Register("github", func(item modules.Item) interactive.Interactable {
+		return NewGitHub(item.(*github.Item))
+	})
+}
+
 type GitHub struct {
+	base
+	*commentsPane
 	item *github.Item
 }
 
+var _ pageset.Commentable = (*GitHub)(nil)
+
 func NewGitHub(item *github.Item) *GitHub {
 	return &GitHub{
-		item: item,
+		base:         base{item: item},
+		commentsPane: &commentsPane{item: item},
+		item:         item,
 	}
 }
 
-func (g *GitHub) Priority() int64 {
-	return g.item.Priority()
-}
+func (g *GitHub) Actions() []pageset.Action {
+	client := g.item.Client()
+	repository := g.item.Repository()
+	issue := g.item.Issue()
 
-func (g *GitHub) Status() string {
-	return g.item.Status()
-}
+	actions := []pageset.Action{
+		{
+			Name:  "comment",
+			Key:   "C",
+			Input: "comment:",
+			Run: func(ctx context.Context, input string) error {
+				return client.AddComment(ctx, repository, issue.Number, input)
+			},
+		},
+		{
+			Name:  "add labels",
+			Key:   "+",
+			Input: "labels (comma separated):",
+			Run: func(ctx context.Context, input string) error {
+				return client.AddLabels(ctx, repository, issue.Number, splitList(input)...)
+			},
+		},
+		{
+			Name:  "remove label",
+			Key:   "-",
+			Input: "label:",
+			Run: func(ctx context.Context, input string) error {
+				return client.RemoveLabel(ctx, repository, issue.Number, input)
+			},
+		},
+		{
+			Name: "assign me",
+			Key:  "a",
+			Run: func(ctx context.Context, _ string) error {
+				login, err := client.CurrentUser(ctx)
+				if err != nil {
+					return err
+				}
+				return client.AddAssignees(ctx, repository, issue.Number, login)
+			},
+		},
+	}
+
+	if issue.State() != "open" {
+		return actions
+	}
 
-func (g *GitHub) Group() string {
-	return g.item.Group()
+	actions = append(actions, pageset.Action{
+		Name: "close",
+		Key:  "x",
+		Run: func(ctx context.Context, _ string) error {
+			return client.Close(ctx, repository, issue.Number)
+		},
+	})
+
+	if issue.IsPullRequest() {
+		actions = append(actions, pageset.Action{
+			Name: "approve",
+			Key:  "A",
+			Run: func(ctx context.Context, _ string) error {
+				return client.Approve(ctx, repository, issue.Number)
+			},
+		})
+	}
+
+	return actions
 }
 
-func (g *GitHub) Render(width int) string {
-	var out strings.Builder
+// Row shows the state of the item in place of an icon.
+func (g *GitHub) Row() pageset.Row {
+	row := g.base.Row()
+	row.Icon = g.symbol()
+	return row
+}
 
+// symbol returns the glyph for the type and state of the item.
+func (g *GitHub) symbol() string {
 	issue := g.item.Issue()
 
-	symbol := ""
 	switch g.item.Type() {
 	case string(github.ItemTypeIssue):
 		switch issue.State() {
 		case "open":
-			symbol = stateOpenStyle.Render(issueOpen)
+			return stateOpenStyle.Render(issueOpen)
 		case "closed":
-			symbol = stateClosedStyle.Render(issueClosed)
+			return stateClosedStyle.Render(issueClosed)
 		}
 	case string(github.ItemTypePullRequest):
 		switch issue.State() {
 		case "open":
-			symbol = stateOpenStyle.Render(prOpen)
+			return stateOpenStyle.Render(prOpen)
 		case "closed":
-			symbol = stateClosedStyle.Render(prClosed)
+			return stateClosedStyle.Render(prClosed)
 		case "merged":
-			symbol = stateMergedStyle.Render(prMerged)
+			return stateMergedStyle.Render(prMerged)
 		}
 	}
 
-	prefixRegex := regexp.MustCompile("^https://api.+/repos/")
-	prefix := prefixRegex.FindString(issue.RepositoryURL)
-	project := strings.TrimPrefix(issue.RepositoryURL, prefix)
-	out.WriteString(projectStyle.Render(fmt.Sprintf("%s  %s", "", project)) + "\n\n")
+	return ""
+}
+
+func (g *GitHub) Render(width int) string {
+	var out strings.Builder
+
+	issue := g.item.Issue()
+
+	out.WriteString(renderStale(g.item.StaleSince()))
+
+	out.WriteString(projectStyle.Render(fmt.Sprintf("%s  %s", "", g.item.Repository())) + "\n\n")
 
-	out.WriteString(titleStyle.Width(width).Render(fmt.Sprintf("%s  %s", symbol, issue.Title)) + "\n\n")
+	out.WriteString(titleStyle.Width(width).Render(fmt.Sprintf("%s  %s", g.symbol(), issue.Title)) + "\n\n")
 
 	out.WriteString(fmt.Sprintf(
 		"%s %s",
@@ -92,6 +173,10 @@ func (g *GitHub) Render(width int) string {
 
 	out.WriteString("\n\n")
 
+	if details := g.item.PullRequestDetails(); details != nil {
+		out.WriteString(renderPullRequestStatus(details))
+	}
+
 	labelsStr := ""
 	for _, label := range issue.Labels {
 		labelsStr += labelStyle.Background(lipgloss.Color(fmt.Sprintf("#%s", label.Color))).Render(label.Name) + " "
@@ -104,11 +189,47 @@ func (g *GitHub) Render(width int) string {
 
 	bodyOut, _ := glamour.Render(issue.Body, "dark")
 	out.WriteString(bodyOut)
+	out.WriteString(renderCustomFields(g.item.CustomFields(), width))
+	out.WriteString(g.commentsPane.Render(width))
 
 	return lipgloss.NewStyle().MarginLeft(2).Render(out.String())
 }
 
-func (g *GitHub) Open() tea.Cmd {
-	cmd := linkopener.New(g.item.URL()).Open()
-	return tea.ExecProcess(cmd, nil)
+// renderPullRequestStatus renders the CI, review and merge
+// state of a pull request from its enriched details.
+func renderPullRequestStatus(details *github.PullRequestDetails) string {
+	parts := []string{}
+
+	if details.Draft {
+		parts = append(parts, projectStyle.Render("draft"))
+	}
+
+	switch details.CheckStatus {
+	case "":
+	case "SUCCESS":
+		parts = append(parts, stateOpenStyle.Render(fmt.Sprintf("%s checks passed", checkPassed)))
+	case "FAILURE", "ERROR":
+		parts = append(parts, stateClosedStyle.Render(fmt.Sprintf("%s checks failed", checkFailed)))
+	default:
+		parts = append(parts, staleStyle.Render(fmt.Sprintf("%s checks pending", checkPending)))
+	}
+
+	switch details.ReviewDecision {
+	case "APPROVED":
+		parts = append(parts, stateOpenStyle.Render("approved"))
+	case "CHANGES_REQUESTED":
+		parts = append(parts, stateClosedStyle.Render("changes requested"))
+	case "REVIEW_REQUIRED":
+		parts = append(parts, projectStyle.Render("review required"))
+	}
+
+	if details.Mergeable == "CONFLICTING" {
+		parts = append(parts, stateClosedStyle.Render("conflicts"))
+	}
+
+	parts = append(parts,
+		stateOpenStyle.Render(fmt.Sprintf("+%d", details.Additions))+" "+stateClosedStyle.Render(fmt.Sprintf("-%d", details.Deletions)),
+	)
+
+	return strings.Join(parts, "  ") + "\n\n"
 }
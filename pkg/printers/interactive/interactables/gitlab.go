@@ -0,0 +1,148 @@
+package interactables
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/everettraven/wranglr/pkg/modules"
+	"github.com/everettraven/wranglr/pkg/modules/gitlab"
+	"github.com/everettraven/wranglr/pkg/printers/interactive"
+	"github.com/everettraven/wranglr/pkg/printers/interactive/models/pageset"
+)
+
+const gitlabIcon = ""
+
+func init() {
+	Register("gitlab", func(item modules.Item) interactive.Interactable {
+		return NewGitLab(item.(*gitlab.Item))
+	})
+}
+
+type GitLab struct {
+	base
+	item *gitlab.Item
+}
+
+func NewGitLab(item *gitlab.Item) *GitLab {
+	return &GitLab{
+		base: base{item: item},
+		item: item,
+	}
+}
+
+// Row shows the state of the item in place of an icon.
+func (g *GitLab) Row() pageset.Row {
+	row := g.base.Row()
+	row.Icon = g.symbol()
+	return row
+}
+
+// symbol returns the glyph for the type and state of the item.
+func (g *GitLab) symbol() string {
+	resource := g.item.Resource()
+	mr := g.item.MergeRequest()
+
+	switch {
+	case mr == nil && resource.State == "opened":
+		return stateOpenStyle.Render(issueOpen)
+	case mr == nil && resource.State == "closed":
+		return stateClosedStyle.Render(issueClosed)
+	case mr != nil && resource.State == "opened":
+		return stateOpenStyle.Render(prOpen)
+	case mr != nil && resource.State == "closed":
+		return stateClosedStyle.Render(prClosed)
+	case mr != nil && resource.State == "merged":
+		return stateMergedStyle.Render(prMerged)
+	}
+
+	return ""
+}
+
+func (g *GitLab) Render(width int) string {
+	var out strings.Builder
+
+	resource := g.item.Resource()
+	mr := g.item.MergeRequest()
+
+	out.WriteString(renderStale(g.item.StaleSince()))
+
+	out.WriteString(projectStyle.Render(fmt.Sprintf("%s  %s", gitlabIcon, g.item.Project())) + "\n\n")
+
+	title := resource.Title
+	if mr != nil && mr.Draft {
+		title = "[Draft] " + title
+	}
+	out.WriteString(titleStyle.Width(width).Render(fmt.Sprintf("%s  %s", g.symbol(), title)) + "\n\n")
+
+	out.WriteString(fmt.Sprintf(
+		"%s %s",
+		projectStyle.Render("by"),
+		titleStyle.Render(fmt.Sprintf("@%s", resource.Author.Username)),
+	))
+	out.WriteString("\n\n")
+
+	out.WriteString(" ")
+	if len(resource.Assignees) > 0 {
+		for _, assignee := range resource.Assignees {
+			out.WriteString(titleStyle.Render(fmt.Sprintf("@%s ", assignee.Username)))
+		}
+	} else {
+		out.WriteString(projectStyle.Render("unassigned"))
+	}
+
+	out.WriteString("\n\n")
+
+	if mr != nil {
+		out.WriteString(renderMergeRequestStatus(mr))
+	}
+
+	labelsStr := ""
+	for _, label := range resource.Labels {
+		labelsStr += labelStyle.Background(lipgloss.Cyan).Render(label) + " "
+	}
+
+	if len(labelsStr) > 0 {
+		out.WriteString(lipgloss.NewStyle().Width(width).Render(labelsStr))
+		out.WriteString("\n")
+	}
+
+	bodyOut, _ := glamour.Render(resource.Description, "dark")
+	out.WriteString(bodyOut)
+	out.WriteString(renderCustomFields(g.item.CustomFields(), width))
+
+	return lipgloss.NewStyle().MarginLeft(2).Render(out.String())
+}
+
+// renderMergeRequestStatus renders the pipeline and approval
+// state of a merge request, if they were fetched.
+func renderMergeRequestStatus(mr *gitlab.MergeRequest) string {
+	var out strings.Builder
+
+	if mr.Pipeline != nil {
+		switch mr.Pipeline.Status {
+		case "success":
+			out.WriteString(stateOpenStyle.Render(fmt.Sprintf("%s pipeline %s", checkPassed, mr.Pipeline.Status)))
+		case "failed", "canceled":
+			out.WriteString(stateClosedStyle.Render(fmt.Sprintf("%s pipeline %s", checkFailed, mr.Pipeline.Status)))
+		default:
+			out.WriteString(staleStyle.Render(fmt.Sprintf("%s pipeline %s", checkPending, mr.Pipeline.Status)))
+		}
+		out.WriteString("  ")
+	}
+
+	if mr.Approvals != nil {
+		if mr.Approvals.Approved {
+			out.WriteString(stateOpenStyle.Render(fmt.Sprintf("%s approved", checkPassed)))
+		} else {
+			out.WriteString(projectStyle.Render(fmt.Sprintf("%d approvals left", mr.Approvals.ApprovalsLeft)))
+		}
+	}
+
+	if out.Len() == 0 {
+		return ""
+	}
+
+	return out.String() + "\n\n"
+}
@@ -1,35 +1,78 @@
 package interactables
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
-	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/everettraven/wranglr/pkg/modules"
 	"github.com/everettraven/wranglr/pkg/modules/jira"
-	"github.com/everettraven/wranglr/pkg/printers/interactive/interactables/linkopener"
+	"github.com/everettraven/wranglr/pkg/printers/interactive"
+	"github.com/everettraven/wranglr/pkg/printers/interactive/models/pageset"
 )
 
+const jiraIcon = ""
+
+func init() {
+	Register("jira", func(item modules.Item) interactive.Interactable {
+		return NewJira(item.(*jira.Item))
+	})
+}
+
 type Jira struct {
+	base
+	*commentsPane
 	item *jira.Item
 }
 
+var _ pageset.Commentable = (*Jira)(nil)
+
 func NewJira(item *jira.Item) *Jira {
 	return &Jira{
-		item: item,
+		base:         base{item: item},
+		commentsPane: &commentsPane{item: item},
+		item:         item,
 	}
 }
 
-func (j *Jira) Priority() int64 {
-	return j.item.Priority()
-}
-
-func (j *Jira) Status() string {
-	return j.item.Status()
+func (j *Jira) Actions() []pageset.Action {
+	client := j.item.Client()
+	api := j.item.API()
+	key := j.item.ID()
+
+	return []pageset.Action{
+		{
+			Name:  "transition",
+			Key:   "t",
+			Input: "transition to:",
+			Run: func(ctx context.Context, input string) error {
+				return client.Transition(ctx, api, key, input)
+			},
+		},
+		{
+			Name: "assign me",
+			Key:  "a",
+			Run: func(ctx context.Context, _ string) error {
+				return client.AssignSelf(ctx, api, key)
+			},
+		},
+		{
+			Name:  "comment",
+			Key:   "C",
+			Input: "comment:",
+			Run: func(ctx context.Context, input string) error {
+				return client.AddComment(ctx, api, key, input)
+			},
+		},
+	}
 }
 
-func (j *Jira) Group() string {
-	return j.item.Group()
+func (j *Jira) Row() pageset.Row {
+	row := j.base.Row()
+	row.Icon = jiraIcon
+	return row
 }
 
 func (j *Jira) Render(width int) string {
@@ -37,15 +80,19 @@ func (j *Jira) Render(width int) string {
 
 	issue := j.item.Issue()
 
-	out.WriteString(projectStyle.Render(fmt.Sprintf("%s %s", "", issue.Key)) + "\n")
+	out.WriteString(renderStale(j.item.StaleSince()))
+
+	out.WriteString(projectStyle.Render(fmt.Sprintf("%s %s", jiraIcon, issue.Key)) + "\n")
 	out.WriteString(titleStyle.Width(width).Render(fmt.Sprintf("[%s] %s", issue.Fields.Type.Name, issue.Fields.Summary)) + "\n")
 
-	out.WriteString(fmt.Sprintf(
-		"%s %s",
-		projectStyle.Render("by"),
-		titleStyle.Render(issue.Fields.Reporter.DisplayName),
-	))
-	out.WriteString("\n")
+	if issue.Fields.Reporter != nil {
+		out.WriteString(fmt.Sprintf(
+			"%s %s",
+			projectStyle.Render("by"),
+			titleStyle.Render(issue.Fields.Reporter.DisplayName),
+		))
+		out.WriteString("\n")
+	}
 
 	out.WriteString("  ")
 	if issue.Fields.Assignee != nil {
@@ -55,7 +102,9 @@ func (j *Jira) Render(width int) string {
 	}
 	out.WriteString("\n\n")
 
-	out.WriteString(issue.Fields.Priority.Name + "\n")
+	if issue.Fields.Priority != nil {
+		out.WriteString(issue.Fields.Priority.Name + "\n")
+	}
 
 	out.WriteString("  ")
 	for _, component := range issue.Fields.Components {
@@ -72,19 +121,10 @@ func (j *Jira) Render(width int) string {
 		out.WriteString("\n")
 	}
 
-	// TODO: Improved rendering for Jira ticket bodies.
-	// This will likely include needing to build an
-	// Atlassian Document Format parsing library
-	// that we can then feed into a glamour-like library
-	// for rendering different types of document nodes (headings, codeblocks, etc.).
-	bodyOut := issue.Fields.Description
-	wrapped := lipgloss.NewStyle().Width(width)
-	out.WriteString(wrapped.Render(bodyStyle.Render(bodyOut)))
+	bodyOut, _ := glamour.Render(j.item.Body(), "dark")
+	out.WriteString(bodyOut)
+	out.WriteString(renderCustomFields(j.item.CustomFields(), width))
+	out.WriteString(j.commentsPane.Render(width))
 
 	return lipgloss.NewStyle().MarginLeft(2).Render(out.String())
 }
-
-func (j *Jira) Open() tea.Cmd {
-	cmd := linkopener.New(j.item.URL()).Open()
-	return tea.ExecProcess(cmd, nil)
-}
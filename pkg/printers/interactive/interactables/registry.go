@@ -0,0 +1,27 @@
+package interactables
+
+import (
+	"github.com/everettraven/wranglr/pkg/modules"
+	"github.com/everettraven/wranglr/pkg/printers/interactive"
+)
+
+// Constructor creates an interactable for an item.
+type Constructor func(item modules.Item) interactive.Interactable
+
+var constructors = map[string]Constructor{}
+
+// Register registers the Constructor used to create interactables for
+// items from the provided source. Sources without a registered Constructor
+// are rendered using the Generic interactable.
+func Register(source string, constructor Constructor) {
+	constructors[source] = constructor
+}
+
+// New creates an interactable for the item using
+// the Constructor registered for the item's source.
+func New(item modules.Item) interactive.Interactable {
+	if constructor, ok := constructors[item.Source()]; ok {
+		return constructor(item)
+	}
+	return NewGeneric(item)
+}
@@ -0,0 +1,18 @@
+package interactables
+
+import (
+	"fmt"
+	"time"
+)
+
+const staleIcon = "󰅤"
+
+// renderStale renders a marker indicating the item is being rendered
+// from cached data, or an empty string if the item is not stale.
+func renderStale(staleSince time.Time) string {
+	if staleSince.IsZero() {
+		return ""
+	}
+
+	return staleStyle.Render(fmt.Sprintf("%s  stale since %s", staleIcon, staleSince.Local().Format(time.DateTime))) + "\n\n"
+}
@@ -0,0 +1,21 @@
+package interactive
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Filter  key.Binding
+	Scope   key.Binding
+	Submit  key.Binding
+	Cancel  key.Binding
+	Refresh key.Binding
+	AllSeen key.Binding
+}
+
+var DefaultKeyMap = KeyMap{
+	Filter:  key.NewBinding(key.WithKeys("/")),
+	Scope:   key.NewBinding(key.WithKeys("tab")),
+	Submit:  key.NewBinding(key.WithKeys("enter")),
+	Cancel:  key.NewBinding(key.WithKeys("esc")),
+	Refresh: key.NewBinding(key.WithKeys("r")),
+	AllSeen: key.NewBinding(key.WithKeys("R")),
+}
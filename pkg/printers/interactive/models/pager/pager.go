@@ -56,6 +56,9 @@ func (p *Model) Update(m tea.Msg) (*Model, tea.Cmd) {
 }
 
 func (p *Model) View() string {
+	if p.totalPages == 0 {
+		return p.style.Render("0 / 0")
+	}
 	return p.style.Render(fmt.Sprintf("%d / %d", p.page+1, p.totalPages))
 }
 
@@ -84,3 +87,19 @@ func (p *Model) decrement() {
 func (p *Model) Page() int {
 	return p.page
 }
+
+// SetTotal sets the total number of pages, moving
+// to the last page if the current page no longer exists.
+func (p *Model) SetTotal(total int) {
+	p.totalPages = total
+	if p.page >= total {
+		p.page = max(total-1, 0)
+	}
+}
+
+// SetPage moves to the provided page if it exists.
+func (p *Model) SetPage(page int) {
+	if page >= 0 && page < p.totalPages {
+		p.page = page
+	}
+}
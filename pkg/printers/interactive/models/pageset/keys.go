@@ -0,0 +1,25 @@
+package pageset
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Open     key.Binding
+	Comments key.Binding
+	List     key.Binding
+	Up       key.Binding
+	Down     key.Binding
+	Confirm  key.Binding
+	Cancel   key.Binding
+	Submit   key.Binding
+}
+
+var DefaultKeyMap = KeyMap{
+	Open:     key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open")),
+	Comments: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "comments")),
+	List:     key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "list")),
+	Up:       key.NewBinding(key.WithKeys("k", "up")),
+	Down:     key.NewBinding(key.WithKeys("j", "down")),
+	Confirm:  key.NewBinding(key.WithKeys("y", "Y")),
+	Cancel:   key.NewBinding(key.WithKeys("esc")),
+	Submit:   key.NewBinding(key.WithKeys("enter")),
+}
@@ -0,0 +1,175 @@
+package pageset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Listable is implemented by pages that can be
+// summarized as a row of the list shown in list mode.
+type Listable interface {
+	Row() Row
+}
+
+// Row is the summary of a page shown in list mode.
+type Row struct {
+	// Icon is a glyph representing the page (i.e the
+	// state of an issue), which may be styled.
+	Icon      string
+	ID        string
+	Title     string
+	Assignees []string
+	CreatedAt time.Time
+	Priority  int64
+	Status    string
+}
+
+const (
+	// splitWidth is the minimum width at which the selected page is shown
+	// beside the list rather than below it.
+	splitWidth = 140
+
+	columnGap = "  "
+)
+
+// renderList renders a row for each page, scrolled so that the
+// row of the current page is visible within the provided height.
+func (ps *PageSet) renderList(width, height int) string {
+	if len(ps.pages) == 0 {
+		return lipgloss.NewStyle().Width(width).Height(height).Render(footerStyle.Render(ps.empty()))
+	}
+
+	rows := []Row{}
+	for _, page := range ps.pages {
+		if listable, ok := page.(Listable); ok {
+			rows = append(rows, listable.Row())
+			continue
+		}
+		rows = append(rows, Row{})
+	}
+
+	cols := columnWidths(rows, width)
+
+	// keep the row of the current page within the rows that fit
+	visible := max(height-1, 1)
+	cursor := ps.pager.Page()
+	if cursor < ps.listOffset {
+		ps.listOffset = cursor
+	}
+	if cursor >= ps.listOffset+visible {
+		ps.listOffset = cursor - visible + 1
+	}
+
+	lines := []string{
+		cell(listHeaderStyle.Render(cols.render("", "", "ID", "TITLE", "ASSIGNEE", "AGE", "PRI", "STATUS")), width),
+	}
+	for i := ps.listOffset; i < len(rows) && i < ps.listOffset+visible; i++ {
+		row := rows[i]
+
+		marker, style := " ", lipgloss.NewStyle()
+		if _, ok := ps.marks[ps.pages[i]]; ok {
+			marker, style = "•", markStyle
+		}
+		if i == cursor {
+			marker, style = "›", selectedRowStyle
+		}
+
+		lines = append(lines, cell(cols.render(
+			style.Render(marker),
+			row.Icon,
+			style.Render(row.ID),
+			style.Render(row.Title),
+			renderAssignees(row.Assignees),
+			formatAge(row.CreatedAt),
+			strconv.FormatInt(row.Priority, 10),
+			row.Status,
+		), width))
+	}
+
+	return lipgloss.NewStyle().Height(height).MaxHeight(height).Render(strings.Join(lines, "\n"))
+}
+
+type columns struct {
+	id, title, assignee, age, priority, status int
+}
+
+// columnWidths sizes the columns to fit their content, up to a limit,
+// giving the title whatever width remains.
+func columnWidths(rows []Row, width int) columns {
+	cols := columns{
+		id:       len("ID"),
+		assignee: len("ASSIGNEE"),
+		age:      len("AGE"),
+		priority: len("PRI"),
+		status:   len("STATUS"),
+	}
+
+	for _, row := range rows {
+		cols.id = max(cols.id, min(lipgloss.Width(row.ID), 24))
+		cols.assignee = max(cols.assignee, min(lipgloss.Width(renderAssignees(row.Assignees)), 16))
+		cols.age = max(cols.age, len(formatAge(row.CreatedAt)))
+		cols.priority = max(cols.priority, len(strconv.FormatInt(row.Priority, 10)))
+		cols.status = max(cols.status, min(lipgloss.Width(row.Status), 16))
+	}
+
+	// the marker and icon are a single character wide
+	used := 1 + 1 + cols.id + cols.assignee + cols.age + cols.priority + cols.status + 7*len(columnGap)
+	cols.title = max(width-used, 10)
+	return cols
+}
+
+func (c columns) render(marker, icon, id, title, assignee, age, priority, status string) string {
+	return strings.Join([]string{
+		cell(marker, 1),
+		cell(icon, 1),
+		cell(id, c.id),
+		cell(title, c.title),
+		cell(assignee, c.assignee),
+		cell(age, c.age),
+		cell(priority, c.priority),
+		cell(status, c.status),
+	}, columnGap)
+}
+
+// cell truncates or pads the text to the width.
+func cell(text string, width int) string {
+	text = ansi.Truncate(text, width, "…")
+	return text + strings.Repeat(" ", max(width-ansi.StringWidth(text), 0))
+}
+
+func renderAssignees(assignees []string) string {
+	switch len(assignees) {
+	case 0:
+		return "-"
+	case 1:
+		return assignees[0]
+	default:
+		return fmt.Sprintf("%s +%d", assignees[0], len(assignees)-1)
+	}
+}
+
+// formatAge formats the time since t in its largest whole unit (i.e "3d").
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	age := time.Since(t)
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	case age < 14*24*time.Hour:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	case age < 365*24*time.Hour:
+		return fmt.Sprintf("%dw", int(age.Hours()/(24*7)))
+	default:
+		return fmt.Sprintf("%dy", int(age.Hours()/(24*365)))
+	}
+}
@@ -1,6 +1,13 @@
 package pageset
 
 import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss/v2"
@@ -20,27 +27,117 @@ type Page interface {
 	Openable
 }
 
-var DefaultStyle = lipgloss.NewStyle().Margin(0, 0, 1, 2)
+// Commentable is implemented by pages that can show comments.
+type Commentable interface {
+	// ToggleComments shows or hides the comments on the page, returning
+	// a command that loads them using ctx if they haven't been loaded yet.
+	ToggleComments(ctx context.Context) tea.Cmd
+}
+
+// Actionable is implemented by pages that have actions
+// that can be performed on them (i.e adding a label).
+type Actionable interface {
+	Actions() []Action
+}
+
+// Action is an action that can be performed on a page.
+// Actions are always confirmed before they are performed.
+type Action struct {
+	// Name describes the action (i.e "add label").
+	Name string
+
+	// Key is the key that performs the action.
+	Key string
+
+	// Input is the prompt for the text the action requires
+	// (i.e "label:"), or empty if the action requires no text.
+	Input string
+
+	// Run performs the action with the entered text.
+	Run func(ctx context.Context, input string) error
+}
+
+// UpdateMsg is a message that updates the state of a page,
+// such as the result of loading data for it in the background.
+type UpdateMsg interface {
+	Update()
+}
+
+// ActionResultMsg is the result of performing an action.
+type ActionResultMsg struct {
+	Action string
+	Err    error
+}
+
+type mode int
+
+const (
+	modeBrowse mode = iota
+	modeInput
+	modeConfirm
+)
 
 type PageSet struct {
 	viewportModel viewport.Model
 	pager         *pager.Model
-	pages         []Page
 	style         lipgloss.Style
+	keys          KeyMap
+
+	// all is every page in the page set and pages is the
+	// pages that match the current filter and aren't hidden.
+	all    []Page
+	pages  []Page
+	query  string
+	match  func(Page) bool
+	hidden func(Page) bool
+
+	// extra returns the actions that can be performed on
+	// a page in addition to the page's own actions.
+	extra func(Page) []Action
+
+	// list is whether the pages are listed above or beside the current
+	// page, and listOffset is the first row shown when they don't all fit.
+	list       bool
+	listOffset int
+	listWidth  int
+	listHeight int
+
+	width  int
+	height int
+
+	// marks are shown alongside pages to draw attention
+	// to them, such as "new" for pages that were just added
+	// or "unseen" for pages that haven't been seen.
+	marks map[Page]string
+
+	mode    mode
+	pending *Action
+	input   textinput.Model
+
+	// notice is shown in the footer until the next key press,
+	// such as the result of performing an action.
+	notice string
+
+	// ctx is used for the work the pages do in the background.
+	ctx context.Context
 }
 
 // TODO: optionality
 func New(pages ...Page) *PageSet {
 	ps := &PageSet{
 		viewportModel: viewport.New(100, 100),
+		all:           pages,
 		pages:         pages,
 		style:         DefaultStyle,
+		keys:          DefaultKeyMap,
+		input:         textinput.New(),
+		ctx:           context.Background(),
 	}
 
 	ps.pager = pager.New(
 		len(pages),
-		func(i int) {
-			ps.viewportModel.SetContent(ps.pages[i].Render(ps.viewportModel.Width))
+		func(int) {
+			ps.render()
 		},
 	)
 
@@ -48,24 +145,235 @@ func New(pages ...Page) *PageSet {
 }
 
 func (ps *PageSet) Init() tea.Cmd {
-	ps.viewportModel.SetContent(ps.pages[ps.pager.Page()].Render(ps.viewportModel.Width))
+	ps.render()
 	return nil
 }
 
+// render renders the current page into the viewport.
+func (ps *PageSet) render() {
+	page := ps.page()
+	if page == nil {
+		ps.viewportModel.SetContent(footerStyle.Render(ps.empty()))
+		return
+	}
+	ps.viewportModel.SetContent(page.Render(ps.viewportModel.Width))
+}
+
+// empty describes why there are no pages to show.
+func (ps *PageSet) empty() string {
+	if ps.query != "" {
+		return "No items match the filter."
+	}
+	return "No items to show."
+}
+
+// page returns the current page, or nil if no pages match the filter.
+func (ps *PageSet) page() Page {
+	if len(ps.pages) == 0 {
+		return nil
+	}
+	return ps.pages[ps.pager.Page()]
+}
+
+// Filter narrows the page set to the pages that match. The query is
+// the text the pages were matched against and is shown alongside
+// the pager. An empty query removes the filter.
+func (ps *PageSet) Filter(query string, match func(Page) bool) {
+	ps.query = query
+	ps.match = match
+	ps.refilter()
+}
+
+// Hide hides the pages for which hidden returns true regardless of the
+// filter, and must be called again whenever that may have changed.
+// A nil hidden function shows every page.
+func (ps *PageSet) Hide(hidden func(Page) bool) {
+	ps.hidden = hidden
+	ps.refilter()
+}
+
+// ExtendActions sets a function returning actions that can be performed
+// on a page in addition to the page's own actions (i.e snoozing it).
+func (ps *PageSet) ExtendActions(extra func(Page) []Action) {
+	ps.extra = extra
+}
+
+// refilter narrows the pages to those that match the filter and aren't hidden.
+func (ps *PageSet) refilter() {
+	current := ps.page()
+
+	ps.pages = []Page{}
+	for _, page := range ps.visible() {
+		if ps.query == "" || ps.match(page) {
+			ps.pages = append(ps.pages, page)
+		}
+	}
+
+	// stay on the current page if it still matches
+	ps.pager.SetTotal(len(ps.pages))
+	ps.pager.SetPage(slices.Index(ps.pages, current))
+	ps.layout()
+}
+
+// visible returns the pages that aren't hidden.
+func (ps *PageSet) visible() []Page {
+	if ps.hidden == nil {
+		return ps.all
+	}
+
+	return slices.DeleteFunc(slices.Clone(ps.all), ps.hidden)
+}
+
+// Current returns the current page, or nil if no pages match the filter.
+func (ps *PageSet) Current() Page {
+	return ps.page()
+}
+
+// Select makes the first matching page the current page, returning
+// whether any page matched. Pages that don't match the filter can't be selected.
+func (ps *PageSet) Select(match func(Page) bool) bool {
+	i := slices.IndexFunc(ps.pages, match)
+	if i < 0 {
+		return false
+	}
+
+	ps.pager.SetPage(i)
+	ps.render()
+	return true
+}
+
+// ListMode returns whether the pages are being listed.
+func (ps *PageSet) ListMode() bool {
+	return ps.list
+}
+
+// SetContext sets the context used for the work the pages do
+// in the background, so that it is cancelled along with it.
+func (ps *PageSet) SetContext(ctx context.Context) {
+	ps.ctx = ctx
+}
+
+// SetListMode sets whether the pages are listed alongside the current page.
+func (ps *PageSet) SetListMode(list bool) {
+	ps.list = list
+	ps.layout()
+}
+
+// Mark marks each page with the text returned for it, removing any
+// existing marks. Pages are not marked if the text is empty, and
+// a nil mark function removes all marks.
+func (ps *PageSet) Mark(mark func(Page) string) {
+	ps.marks = map[Page]string{}
+	if mark == nil {
+		return
+	}
+
+	for _, page := range ps.all {
+		if text := mark(page); text != "" {
+			ps.marks[page] = text
+		}
+	}
+}
+
+// layout sizes the viewport to fit beside or below the list in list
+// mode, or to fill the page set otherwise, and renders the current page.
+func (ps *PageSet) layout() {
+	height := ps.height - lipgloss.Height(ps.status()) - lipgloss.Height(ps.footer())
+
+	switch {
+	case !ps.list:
+		ps.viewportModel.Width = ps.width
+		ps.viewportModel.Height = height
+	case ps.width >= splitWidth:
+		ps.listWidth = ps.width / 2
+		ps.listHeight = height
+		ps.viewportModel.Width = ps.width - ps.listWidth - len(columnGap)
+		ps.viewportModel.Height = height
+	default:
+		// a blank line separates the list from the page
+		ps.listWidth = ps.width
+		ps.listHeight = max(min(len(ps.pages)+1, height/3), 3)
+		ps.viewportModel.Width = ps.width
+		ps.viewportModel.Height = max(height-ps.listHeight-1, 0)
+	}
+
+	ps.render()
+}
+
+// move moves to the page offset from the current page by delta.
+func (ps *PageSet) move(delta int) {
+	ps.pager.SetPage(ps.pager.Page() + delta)
+	ps.viewportModel.GotoTop()
+	ps.render()
+}
+
+// Capturing returns whether key presses are being captured for
+// entering text or confirming an action, in which case they
+// shouldn't be handled by any parent models.
+func (ps *PageSet) Capturing() bool {
+	return ps.mode != modeBrowse
+}
+
 func (ps *PageSet) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := message.(type) {
 	case tea.WindowSizeMsg:
-		ps.viewportModel.Width = msg.Width
-		ps.viewportModel.Height = msg.Height - lipgloss.Height(ps.pager.View())
+		ps.width = msg.Width
+		ps.height = msg.Height
+		ps.input.Width = msg.Width
+		ps.layout()
+		return ps, nil
+
+	case UpdateMsg:
+		// the message may be for a page in another page set, but it
+		// is only delivered to the page set that is currently shown
+		msg.Update()
+		ps.render()
+		return ps, nil
+
+	case ActionResultMsg:
+		if msg.Err != nil {
+			ps.notice = failedStyle.Render(fmt.Sprintf("%s failed: %v", msg.Action, msg.Err))
+		} else {
+			ps.notice = succeededStyle.Render(fmt.Sprintf("%s succeeded", msg.Action))
+		}
 		return ps, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		// TODO: make this "action-oriented"
-		// where a page can define "actions" that it
-		// supports and then can be dynamically supported.
-		case "o":
-			return ps, ps.pages[ps.pager.Page()].Open()
+		switch ps.mode {
+		case modeInput:
+			return ps.updateInput(msg)
+		case modeConfirm:
+			return ps.updateConfirm(msg)
+		}
+
+		ps.notice = ""
+
+		switch {
+		case key.Matches(msg, ps.keys.List):
+			ps.SetListMode(!ps.list)
+			return ps, nil
+		case ps.list && key.Matches(msg, ps.keys.Down):
+			ps.move(1)
+			return ps, nil
+		case ps.list && key.Matches(msg, ps.keys.Up):
+			ps.move(-1)
+			return ps, nil
+		case ps.page() == nil:
+			// there is nothing to act on until the filter is changed
+		case key.Matches(msg, ps.keys.Open):
+			return ps, ps.page().Open()
+		case key.Matches(msg, ps.keys.Comments):
+			if page, ok := ps.page().(Commentable); ok {
+				cmd := page.ToggleComments(ps.ctx)
+				ps.render()
+				return ps, cmd
+			}
+		}
+
+		for _, action := range ps.actions() {
+			if msg.String() == action.Key {
+				return ps, ps.start(action)
+			}
 		}
 	}
 
@@ -77,12 +385,161 @@ func (ps *PageSet) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 	return ps, cmd
 }
 
+func (ps *PageSet) actions() []Action {
+	actions := []Action{}
+	if page, ok := ps.page().(Actionable); ok {
+		actions = append(actions, page.Actions()...)
+	}
+	if ps.extra != nil && ps.page() != nil {
+		actions = append(actions, ps.extra(ps.page())...)
+	}
+	return actions
+}
+
+// start starts performing an action, prompting
+// for its input first if it requires any.
+func (ps *PageSet) start(action Action) tea.Cmd {
+	ps.pending = &action
+
+	if action.Input == "" {
+		ps.mode = modeConfirm
+		return nil
+	}
+
+	ps.mode = modeInput
+	ps.input.Reset()
+	ps.input.Prompt = action.Input + " "
+	return ps.input.Focus()
+}
+
+func (ps *PageSet) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, ps.keys.Cancel):
+		ps.cancel()
+		return ps, nil
+	case key.Matches(msg, ps.keys.Submit):
+		if strings.TrimSpace(ps.input.Value()) == "" {
+			ps.cancel()
+			return ps, nil
+		}
+		ps.input.Blur()
+		ps.mode = modeConfirm
+		return ps, nil
+	}
+
+	var cmd tea.Cmd
+	ps.input, cmd = ps.input.Update(msg)
+	return ps, cmd
+}
+
+func (ps *PageSet) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if !key.Matches(msg, ps.keys.Confirm) {
+		ps.cancel()
+		return ps, nil
+	}
+
+	action := *ps.pending
+	input := strings.TrimSpace(ps.input.Value())
+	if action.Input == "" {
+		input = ""
+	}
+
+	ps.mode = modeBrowse
+	ps.pending = nil
+	ps.notice = footerStyle.Render(action.Name + "...")
+
+	ctx := ps.ctx
+	return ps, func() tea.Msg {
+		err := action.Run(ctx, input)
+		return ActionResultMsg{Action: action.Name, Err: err}
+	}
+}
+
+func (ps *PageSet) cancel() {
+	ps.mode = modeBrowse
+	ps.pending = nil
+	ps.input.Blur()
+	ps.notice = footerStyle.Render("cancelled")
+}
+
+// footer renders the line below the pager, which is either the prompt
+// for the pending action, a notice or the keys for the available actions.
+func (ps *PageSet) footer() string {
+	line := lipgloss.NewStyle().MaxWidth(ps.viewportModel.Width).MaxHeight(1)
+
+	switch {
+	case ps.mode == modeInput:
+		return line.Render(ps.input.View())
+	case ps.mode == modeConfirm:
+		prompt := ps.pending.Name
+		if ps.pending.Input != "" {
+			prompt += fmt.Sprintf(" %q", strings.TrimSpace(ps.input.Value()))
+		}
+		return line.Render(promptStyle.Render(prompt + "? (y/n)"))
+	case ps.notice != "":
+		return line.Render(ps.notice)
+	}
+
+	if ps.page() == nil {
+		if ps.query == "" {
+			return ""
+		}
+		return line.Render(footerStyle.Render(footerKeyStyle.Render("esc") + " clear filter"))
+	}
+
+	list := ps.keys.List.Help().Desc
+	if ps.list {
+		list = "pages"
+	}
+
+	keys := []string{
+		footerKeyStyle.Render(ps.keys.List.Help().Key) + " " + list,
+		footerKeyStyle.Render(ps.keys.Open.Help().Key) + " " + ps.keys.Open.Help().Desc,
+	}
+	if _, ok := ps.page().(Commentable); ok {
+		keys = append(keys, footerKeyStyle.Render(ps.keys.Comments.Help().Key)+" "+ps.keys.Comments.Help().Desc)
+	}
+	for _, action := range ps.actions() {
+		keys = append(keys, footerKeyStyle.Render(action.Key)+" "+action.Name)
+	}
+
+	return line.Render(footerStyle.Render(strings.Join(keys, " • ")))
+}
+
+// status renders the pager along with the mark
+// of the current page and the filter, if there are any.
+func (ps *PageSet) status() string {
+	parts := []string{ps.pager.View()}
+
+	if mark, ok := ps.marks[ps.page()]; ok {
+		parts = append(parts, markStyle.Render("  "+mark))
+	}
+
+	if ps.query != "" {
+		parts = append(parts, footerStyle.Render(fmt.Sprintf("  filter %q matches %d of %d", ps.query, len(ps.pages), len(ps.visible()))))
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+}
+
 func (ps *PageSet) View() string {
+	content := ps.viewportModel.View()
+
+	if ps.list {
+		list := ps.renderList(ps.listWidth, ps.listHeight)
+		if ps.width >= splitWidth {
+			content = lipgloss.JoinHorizontal(lipgloss.Top, list, columnGap, content)
+		} else {
+			content = lipgloss.JoinVertical(lipgloss.Left, list, "", content)
+		}
+	}
+
 	return ps.style.Render(
 		lipgloss.JoinVertical(
 			lipgloss.Top,
-			ps.viewportModel.View(),
-			ps.pager.View(),
+			content,
+			ps.status(),
+			ps.footer(),
 		),
 	)
 }
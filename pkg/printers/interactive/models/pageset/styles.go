@@ -0,0 +1,17 @@
+package pageset
+
+import "github.com/charmbracelet/lipgloss/v2"
+
+var (
+	DefaultStyle = lipgloss.NewStyle().Margin(0, 0, 1, 2)
+
+	footerStyle    = lipgloss.NewStyle().Faint(true)
+	footerKeyStyle = lipgloss.NewStyle().Bold(true)
+	promptStyle    = lipgloss.NewStyle().Foreground(lipgloss.Yellow)
+	succeededStyle = lipgloss.NewStyle().Foreground(lipgloss.Green)
+	failedStyle    = lipgloss.NewStyle().Foreground(lipgloss.Red)
+
+	listHeaderStyle  = lipgloss.NewStyle().Faint(true).Bold(true)
+	selectedRowStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Cyan)
+	markStyle        = lipgloss.NewStyle().Foreground(lipgloss.Yellow)
+)
@@ -1,6 +1,8 @@
 package tabs
 
 import (
+	"slices"
+
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss/v2"
@@ -11,6 +13,12 @@ type Tab struct {
 	Model tea.Model
 }
 
+// Capturer is implemented by models that can capture key presses,
+// such as while text is being entered into them.
+type Capturer interface {
+	Capturing() bool
+}
+
 type DisplayFormat string
 
 const (
@@ -73,6 +81,10 @@ func (t *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 		return t, nil
 
 	case tea.KeyMsg:
+		if t.Capturing() {
+			break
+		}
+
 		if key.Matches(msg, t.keys.Next) {
 			t.increment()
 		}
@@ -82,12 +94,57 @@ func (t *Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	if len(t.tabs) == 0 {
+		return t, nil
+	}
+
 	var cmd tea.Cmd
 	t.tabs[t.idx].Model, cmd = t.tabs[t.idx].Model.Update(message)
 	return t, cmd
 }
 
+// Capturing returns whether the model of the current tab is capturing
+// key presses, in which case they aren't used for switching tabs.
+func (t *Model) Capturing() bool {
+	if len(t.tabs) == 0 {
+		return false
+	}
+
+	capturer, ok := t.tabs[t.idx].Model.(Capturer)
+	return ok && capturer.Capturing()
+}
+
+// Active returns the current tab, or an empty
+// tab without a model if there are no tabs.
+func (t *Model) Active() Tab {
+	if len(t.tabs) == 0 {
+		return Tab{}
+	}
+	return t.tabs[t.idx]
+}
+
+// Tabs returns all of the tabs.
+func (t *Model) Tabs() []Tab {
+	return slices.Clone(t.tabs)
+}
+
+// Select makes the tab with the provided name the current
+// tab, returning whether there is a tab with the name.
+func (t *Model) Select(name string) bool {
+	for i, tab := range t.tabs {
+		if tab.Name == name {
+			t.idx = i
+			return true
+		}
+	}
+	return false
+}
+
 func (t *Model) View() string {
+	if len(t.tabs) == 0 {
+		return ""
+	}
+
 	tabs := ""
 	if len(t.tabs) > 1 {
 		tabs = t.RenderTabs()
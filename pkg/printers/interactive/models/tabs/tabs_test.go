@@ -0,0 +1,24 @@
+package tabs
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNoTabs(t *testing.T) {
+	m := New(nil)
+
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+
+	if m.Capturing() {
+		t.Error("expected a model without tabs not to be capturing")
+	}
+	if tab := m.Active(); tab.Model != nil || tab.Name != "" {
+		t.Errorf("got active tab %+v, want an empty tab", tab)
+	}
+	if view := m.View(); view != "" {
+		t.Errorf("got view %q, want an empty view", view)
+	}
+}
@@ -0,0 +1,275 @@
+package interactive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/everettraven/wranglr/pkg/printers/interactive/models/pageset"
+	"github.com/everettraven/wranglr/pkg/printers/interactive/models/tabs"
+)
+
+// refreshedDuration is how long new and updated
+// items are marked for after a refresh.
+const refreshedDuration = 10 * time.Second
+
+// RefreshFunc returns the refreshed entries, and any
+// warnings to show alongside the result of the refresh.
+type RefreshFunc func(ctx context.Context) (entries []Interactable, warnings []string, err error)
+
+// Identifiable is implemented by interactables that can be matched
+// with their refreshed versions so that the current selection can
+// be kept and changes can be highlighted when refreshing.
+type Identifiable interface {
+	// Identity returns an identifier that is unique across all sources.
+	Identity() string
+
+	// Revision returns when the entry was last updated.
+	Revision() time.Time
+}
+
+// refreshTickMsg starts an automatic refresh. Only ticks from the most
+// recently scheduled tick are used, so that there is only ever one chain
+// of automatic refreshes regardless of how many refreshes are started
+// manually.
+type refreshTickMsg struct {
+	tick int
+}
+
+type refreshedMsg struct {
+	entries  []Interactable
+	warnings []string
+	err      error
+}
+
+// clearRefreshMsg clears the notice and marks from a refresh.
+type clearRefreshMsg struct {
+	generation int
+}
+
+// SetRefresh sets the function used to refresh the entries when
+// pressing r, and the interval they are automatically refreshed at.
+// Entries are only automatically refreshed when the interval is greater than 0.
+func (r *Root) SetRefresh(refresh RefreshFunc, interval time.Duration) {
+	r.refresh = refresh
+	r.interval = interval
+}
+
+// tick schedules the next automatic refresh,
+// replacing any that was already scheduled.
+func (r *Root) tick() tea.Cmd {
+	if r.refresh == nil || r.interval <= 0 {
+		return nil
+	}
+
+	r.ticks++
+	tick := r.ticks
+	return tea.Tick(r.interval, func(time.Time) tea.Msg {
+		return refreshTickMsg{tick: tick}
+	})
+}
+
+// ticked starts an automatic refresh, unless the tick was
+// replaced by a tick scheduled after it.
+func (r *Root) ticked(msg refreshTickMsg) tea.Cmd {
+	if msg.tick != r.ticks {
+		return nil
+	}
+	return r.startRefresh()
+}
+
+// startRefresh refreshes the entries in the background,
+// unless they are already being refreshed.
+func (r *Root) startRefresh() tea.Cmd {
+	if r.refresh == nil || r.refreshing {
+		return nil
+	}
+
+	r.refreshing = true
+	r.notice = noticeStyle.Render("refreshing...")
+
+	refresh := r.refresh
+	ctx := r.ctx
+	return tea.Batch(r.resize(), func() tea.Msg {
+		entries, warnings, err := refresh(ctx)
+		return refreshedMsg{entries: entries, warnings: warnings, err: err}
+	})
+}
+
+func (r *Root) refreshed(msg refreshedMsg) tea.Cmd {
+	r.refreshing = false
+
+	if msg.err != nil {
+		r.generation++
+		r.notice = failedStyle.Render(fmt.Sprintf("refresh failed: %v", msg.err))
+		return tea.Batch(r.resize(), r.clearRefreshAfter(), r.tick())
+	}
+
+	// replacing the tabs would lose any text being entered
+	if r.filtering || r.tabs.Capturing() {
+		r.pending = &msg
+		return r.tick()
+	}
+
+	return tea.Batch(r.applyRefresh(msg), r.tick())
+}
+
+// applyRefresh replaces the entries with the refreshed entries, keeping
+// the current tabs, selected entry, list modes and filter, and marks
+// the entries that are new or were updated.
+func (r *Root) applyRefresh(msg refreshedMsg) tea.Cmd {
+	// there would be nothing to show, such as when the query no
+	// longer matches anything or every item was snoozed, so the
+	// current entries are kept rather than emptying the view
+	if len(msg.entries) == 0 {
+		r.generation++
+		r.notice = noticeStyle.Render(fmt.Sprintf("refreshed at %s: no items, showing the previous items", time.Now().Format(time.TimeOnly)))
+		if len(msg.warnings) > 0 {
+			r.notice += " " + failedStyle.Render(describeWarnings(msg.warnings))
+		}
+		return tea.Batch(r.resize(), r.clearRefreshAfter())
+	}
+
+	group := r.tabs.Active()
+	status := ""
+	if statuses, ok := group.Model.(*tabs.Model); ok {
+		status = statuses.Active().Name
+	}
+
+	selected := ""
+	if ps := activePageSet(r.tabs); ps != nil {
+		if identifiable, ok := ps.Current().(Identifiable); ok {
+			selected = identifiable.Identity()
+		}
+	}
+
+	listModes := map[string]bool{}
+	eachPageSet(r.tabs, func(group, status string, ps *pageset.PageSet) {
+		listModes[group+"/"+status] = ps.ListMode()
+	})
+
+	previous := revisions(r.entries)
+	current := revisions(msg.entries)
+
+	marks := map[string]string{}
+	for identity, revision := range current {
+		prev, ok := previous[identity]
+		switch {
+		case !ok:
+			marks[identity] = "new"
+		case !revision.Equal(prev):
+			marks[identity] = "updated"
+		}
+	}
+
+	removed := 0
+	for identity := range previous {
+		if _, ok := current[identity]; !ok {
+			removed++
+		}
+	}
+
+	r.entries = msg.entries
+	r.tabs = newTabs(msg.entries...)
+	r.refreshMarks = marks
+	cmd := r.tabs.Init()
+
+	if r.tabs.Select(group.Name) {
+		if statuses, ok := r.tabs.Active().Model.(*tabs.Model); ok {
+			statuses.Select(status)
+		}
+	}
+
+	eachPageSet(r.tabs, func(group, status string, ps *pageset.PageSet) {
+		if listModes[group+"/"+status] {
+			ps.SetListMode(true)
+		}
+	})
+	r.attachContext()
+	r.attachSnoozes()
+	r.remark()
+
+	r.applyFilter(r.query)
+
+	if ps := activePageSet(r.tabs); ps != nil && selected != "" {
+		ps.Select(func(page pageset.Page) bool {
+			identifiable, ok := page.(Identifiable)
+			return ok && identifiable.Identity() == selected
+		})
+	}
+
+	r.generation++
+	r.notice = noticeStyle.Render(fmt.Sprintf("refreshed at %s: %s", time.Now().Format(time.TimeOnly), describeChanges(marks, removed)))
+	if len(msg.warnings) > 0 {
+		r.notice += " " + failedStyle.Render(describeWarnings(msg.warnings))
+	}
+
+	return tea.Batch(cmd, r.resize(), r.clearRefreshAfter())
+}
+
+// describeChanges describes the number of new, updated and removed entries.
+func describeChanges(marks map[string]string, removed int) string {
+	added, updated := 0, 0
+	for _, mark := range marks {
+		if mark == "new" {
+			added++
+			continue
+		}
+		updated++
+	}
+
+	changes := []string{}
+	if added > 0 {
+		changes = append(changes, fmt.Sprintf("%d new", added))
+	}
+	if updated > 0 {
+		changes = append(changes, fmt.Sprintf("%d updated", updated))
+	}
+	if removed > 0 {
+		changes = append(changes, fmt.Sprintf("%d removed", removed))
+	}
+
+	if len(changes) == 0 {
+		return "no changes"
+	}
+	return strings.Join(changes, ", ")
+}
+
+// describeWarnings describes the warnings from a refresh, which only
+// includes the first of them as the notice is a single line.
+func describeWarnings(warnings []string) string {
+	if len(warnings) == 1 {
+		return "warning: " + warnings[0]
+	}
+	return fmt.Sprintf("%d warnings, first: %s", len(warnings), warnings[0])
+}
+
+func (r *Root) clearRefreshAfter() tea.Cmd {
+	generation := r.generation
+	return tea.Tick(refreshedDuration, func(time.Time) tea.Msg {
+		return clearRefreshMsg{generation: generation}
+	})
+}
+
+func (r *Root) clearRefresh(msg clearRefreshMsg) {
+	if msg.generation != r.generation {
+		return
+	}
+
+	r.notice = ""
+	r.refreshMarks = nil
+	r.remark()
+}
+
+// revisions returns the revision of each identifiable entry by its identity.
+func revisions(entries []Interactable) map[string]time.Time {
+	revs := map[string]time.Time{}
+	for _, entry := range entries {
+		if identifiable, ok := entry.(Identifiable); ok {
+			revs[identifiable.Identity()] = identifiable.Revision()
+		}
+	}
+	return revs
+}
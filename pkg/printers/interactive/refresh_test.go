@@ -0,0 +1,29 @@
+package interactive
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRefreshWithoutEntries(t *testing.T) {
+	r := NewRoot()
+	r.SetRefresh(func(context.Context) ([]Interactable, []string, error) {
+		return nil, []string{"using cached response"}, nil
+	}, 0)
+
+	r.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	r.Update(refreshedMsg{warnings: []string{"using cached response"}})
+	r.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+
+	if !strings.Contains(r.notice, "no items") {
+		t.Errorf("got notice %q, want it to say there were no items", r.notice)
+	}
+	if !strings.Contains(r.notice, "using cached response") {
+		t.Errorf("got notice %q, want it to include the warning", r.notice)
+	}
+
+	_ = r.View()
+}
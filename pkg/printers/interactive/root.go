@@ -2,10 +2,16 @@ package interactive
 
 import (
 	"cmp"
+	"context"
+	"fmt"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/everettraven/wranglr/pkg/printers/interactive/models/pageset"
 	"github.com/everettraven/wranglr/pkg/printers/interactive/models/tabs"
 )
@@ -39,6 +45,36 @@ type Interactable interface {
 }
 
 func NewRoot(entries ...Interactable) *Root {
+	input := textinput.New()
+	input.Prompt = "/"
+
+	r := &Root{
+		entries: entries,
+		tabs:    newTabs(entries...),
+		keys:    DefaultKeyMap,
+		input:   input,
+		ctx:     context.Background(),
+	}
+	return r
+}
+
+// SetContext sets the context used for the work done in the background,
+// such as refreshing the entries, so that it is cancelled along with it.
+func (r *Root) SetContext(ctx context.Context) {
+	r.ctx = ctx
+	r.attachContext()
+}
+
+// attachContext sets the context of every page set,
+// which is needed whenever the entries have been refreshed.
+func (r *Root) attachContext() {
+	for _, ps := range pageSets(r.tabs) {
+		ps.SetContext(r.ctx)
+	}
+}
+
+// newTabs creates the group and status tabs for the entries.
+func newTabs(entries ...Interactable) *tabs.Model {
 	// sort by priority score
 	slices.SortFunc(entries, func(a, b Interactable) int {
 		return cmp.Compare(a.Priority(), b.Priority())
@@ -47,19 +83,14 @@ func NewRoot(entries ...Interactable) *Root {
 	// to sort in inverse order where higher priority is first
 	slices.Reverse(entries)
 
-	groupedStatusedPages := pagesByGroupAndStatus(entries...)
-
-	return &Root{
-		tabs: groupedTabs(groupedStatusedPages),
-	}
+	return groupedTabs(pagesByGroupAndStatus(entries...))
 }
 
 func groupedTabs(groups GroupedStatusedPages) *tabs.Model {
 	tabList := []tabs.Tab{}
 	for k, v := range groups {
-		normalizedKey := strings.TrimSuffix(strings.TrimPrefix(k, "\""), "\"")
 		tabList = append(tabList, tabs.Tab{
-			Name:  normalizedKey,
+			Name:  k,
 			Model: statusTabs(v),
 		})
 	}
@@ -85,9 +116,8 @@ func statusTabs(statuses StatusedPages) *tabs.Model {
 			continue
 		}
 
-		normalizedKey := strings.TrimSuffix(strings.TrimPrefix(k, "\""), "\"")
 		tabList = append(tabList, tabs.Tab{
-			Name:  normalizedKey,
+			Name:  k,
 			Model: pageset.New(v...),
 		})
 	}
@@ -132,19 +162,124 @@ func pagesByStatus(entries ...Interactable) StatusedPages {
 }
 
 type Root struct {
-	tabs *tabs.Model
+	entries []Interactable
+	tabs    *tabs.Model
+	keys    KeyMap
+	size    tea.WindowSizeMsg
+
+	// input is the filter prompt, which is shown while filtering is true.
+	input     textinput.Model
+	filtering bool
+
+	// query is the current filter and global is whether it
+	// applies to every tab or only the tab it was entered in.
+	query  string
+	global bool
+
+	// ctx is used for the work done in the background, such as
+	// refreshing the entries or running actions on them.
+	ctx context.Context
+
+	refresh    RefreshFunc
+	interval   time.Duration
+	refreshing bool
+
+	// ticks is the number of automatic refreshes that have been
+	// scheduled, which identifies the most recently scheduled one.
+	ticks int
+
+	// refreshMarks marks the entries that are new or were
+	// updated by the most recent refresh by their identity.
+	refreshMarks map[string]string
+
+	// tracker records which entries have been seen and
+	// shown is the entry that is currently shown.
+	tracker Tracker
+	shown   pageset.Page
+
+	// showSnoozed is whether entries that
+	// were snoozed or dismissed are shown.
+	showSnoozed bool
+
+	// pending is a refresh that is applied once key
+	// presses are no longer being captured.
+	pending *refreshedMsg
+
+	// notice is shown below the tabs, such as
+	// the result of the most recent refresh.
+	notice string
+
+	// generation is incremented by every refresh so that messages
+	// scheduled by earlier refreshes can be ignored.
+	generation int
 }
 
 func (r *Root) Init() tea.Cmd {
-	return r.tabs.Init()
+	return tea.Batch(r.tabs.Init(), r.tick())
 }
 
 func (r *Root) Update(message tea.Msg) (tea.Model, tea.Cmd) {
+	cmd := r.update(message)
+
+	if r.pending != nil && !r.filtering && !r.tabs.Capturing() {
+		msg := *r.pending
+		r.pending = nil
+		cmd = tea.Batch(cmd, r.applyRefresh(msg))
+	}
+
+	r.trackShown()
+
+	return r, cmd
+}
+
+func (r *Root) update(message tea.Msg) tea.Cmd {
 	switch msg := message.(type) {
+	case refreshTickMsg:
+		return r.ticked(msg)
+
+	case refreshedMsg:
+		return r.refreshed(msg)
+
+	case clearRefreshMsg:
+		r.clearRefresh(msg)
+		return r.resize()
+
+	case tea.WindowSizeMsg:
+		r.size = msg
+		return r.resize()
+
+	case pageset.ActionResultMsg:
+		// the action may have snoozed the entry, but the
+		// result is still shown by the page set below
+		r.hideSnoozed()
+		r.remark()
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc", "ctrl+c":
-			return r, tea.Quit
+		if msg.String() == "ctrl+c" {
+			r.markCurrentSeen()
+			return tea.Quit
+		}
+
+		if r.filtering {
+			return r.updateFilter(msg)
+		}
+
+		if !r.tabs.Capturing() {
+			switch {
+			case key.Matches(msg, r.keys.Filter):
+				return r.startFilter()
+			case key.Matches(msg, r.keys.Refresh):
+				return r.startRefresh()
+			case key.Matches(msg, r.keys.AllSeen):
+				r.markAllSeen()
+				return nil
+			case key.Matches(msg, r.keys.Cancel) && r.query != "":
+				r.applyFilter("")
+				return nil
+			case msg.String() == "q", msg.String() == "esc":
+				r.markCurrentSeen()
+				return tea.Quit
+			}
 		}
 	}
 
@@ -154,9 +289,141 @@ func (r *Root) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 	tabsModel, cmd = r.tabs.Update(message)
 
 	r.tabs = tabsModel.(*tabs.Model)
-	return r, cmd
+	return cmd
+}
+
+// resize resizes the tabs to fit above the filter prompt or notice.
+func (r *Root) resize() tea.Cmd {
+	size := r.size
+	if bottom := r.bottom(); bottom != "" {
+		size.Height -= lipgloss.Height(bottom)
+	}
+
+	_, cmd := r.tabs.Update(size)
+	return cmd
+}
+
+func (r *Root) startFilter() tea.Cmd {
+	r.filtering = true
+	r.input.SetValue(r.query)
+	r.input.CursorEnd()
+	return tea.Batch(r.input.Focus(), r.resize())
+}
+
+func (r *Root) stopFilter() tea.Cmd {
+	r.filtering = false
+	r.input.Blur()
+	return r.resize()
+}
+
+// updateFilter handles key presses while the filter prompt is
+// shown, narrowing the items as the filter is entered.
+func (r *Root) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, r.keys.Cancel):
+		r.applyFilter("")
+		return r.stopFilter()
+	case key.Matches(msg, r.keys.Submit):
+		return r.stopFilter()
+	case key.Matches(msg, r.keys.Scope):
+		r.global = !r.global
+		r.applyFilter(r.input.Value())
+		return nil
+	}
+
+	var cmd tea.Cmd
+	r.input, cmd = r.input.Update(msg)
+	if strings.TrimSpace(r.input.Value()) != r.query {
+		r.applyFilter(r.input.Value())
+	}
+	return cmd
+}
+
+// applyFilter filters the items of the current tab, or every tab if
+// the filter is global, removing the filter from any other tabs.
+func (r *Root) applyFilter(text string) {
+	r.query = strings.TrimSpace(text)
+	q := parseQuery(r.query)
+	match := func(page pageset.Page) bool {
+		searchable, ok := page.(Searchable)
+		return ok && q.matches(searchable.SearchFields())
+	}
+
+	active := activePageSet(r.tabs)
+	for _, ps := range pageSets(r.tabs) {
+		if r.global || ps == active {
+			ps.Filter(r.query, match)
+			continue
+		}
+		ps.Filter("", nil)
+	}
+}
+
+func (r *Root) prompt() string {
+	scope := "current tab"
+	if r.global {
+		scope = "all tabs"
+	}
+
+	return promptStyle.MaxWidth(r.size.Width).Render(
+		r.input.View() + scopeStyle.Render(fmt.Sprintf("  filtering %s (tab to change)", scope)),
+	)
+}
+
+// bottom renders the line below the tabs, which is either the filter
+// prompt or a notice. It is empty if there is neither.
+func (r *Root) bottom() string {
+	switch {
+	case r.filtering:
+		return r.prompt()
+	case r.notice != "":
+		return promptStyle.MaxWidth(r.size.Width).Render(r.notice)
+	default:
+		return ""
+	}
 }
 
 func (r *Root) View() string {
+	if bottom := r.bottom(); bottom != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, r.tabs.View(), bottom)
+	}
 	return r.tabs.View()
 }
+
+// eachPageSet calls fn with every page set within the group and
+// status tabs, along with the names of the tabs it is within.
+func eachPageSet(groups *tabs.Model, fn func(group, status string, ps *pageset.PageSet)) {
+	for _, group := range groups.Tabs() {
+		statuses, ok := group.Model.(*tabs.Model)
+		if !ok {
+			continue
+		}
+
+		for _, status := range statuses.Tabs() {
+			if ps, ok := status.Model.(*pageset.PageSet); ok {
+				fn(group.Name, status.Name, ps)
+			}
+		}
+	}
+}
+
+// pageSets returns every page set within the group and status tabs.
+func pageSets(groups *tabs.Model) []*pageset.PageSet {
+	sets := []*pageset.PageSet{}
+	eachPageSet(groups, func(_, _ string, ps *pageset.PageSet) {
+		sets = append(sets, ps)
+	})
+	return sets
+}
+
+// activePageSet returns the page set that is currently shown.
+func activePageSet(model tea.Model) *pageset.PageSet {
+	switch m := model.(type) {
+	case *pageset.PageSet:
+		return m
+	case *tabs.Model:
+		return activePageSet(m.Active().Model)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package interactive
+
+import (
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/printers/interactive/models/pageset"
+	"github.com/everettraven/wranglr/pkg/state"
+)
+
+// Tracker records which entries have been seen or snoozed.
+type Tracker interface {
+	Seen(url string, updatedAt time.Time) bool
+	MarkSeen(url string, updatedAt time.Time)
+
+	Snoozed(url string, updatedAt time.Time) (state.Snooze, bool)
+	Snooze(url string, snooze state.Snooze)
+	Restore(url string)
+}
+
+// Trackable is implemented by entries whose seen state can be tracked.
+type Trackable interface {
+	URL() string
+
+	// Revision returns when the entry was last updated.
+	Revision() time.Time
+}
+
+// asTrackable returns v as a Trackable if its seen and snoozed state
+// can be tracked, which requires it to have a URL to track it by.
+func asTrackable(v any) (Trackable, bool) {
+	trackable, ok := v.(Trackable)
+	if !ok || trackable.URL() == "" {
+		return nil, false
+	}
+	return trackable, true
+}
+
+// SetTracker sets the Tracker used to mark the entries that haven't
+// been seen since they were last updated and to record which have.
+// Entries can be snoozed once it is set, and snoozed entries are
+// hidden unless showSnoozed is true.
+func (r *Root) SetTracker(tracker Tracker, showSnoozed bool) {
+	r.tracker = tracker
+	r.showSnoozed = showSnoozed
+	r.attachSnoozes()
+	r.remark()
+}
+
+// remark marks the entries of every page set, which is needed
+// whenever the entries have been refreshed or seen.
+func (r *Root) remark() {
+	for _, ps := range pageSets(r.tabs) {
+		ps.Mark(r.mark)
+	}
+}
+
+// mark returns the mark for an entry, which is whether it is new or was
+// updated by the most recent refresh, whether it is snoozed, or whether
+// it hasn't been seen.
+func (r *Root) mark(page pageset.Page) string {
+	if identifiable, ok := page.(Identifiable); ok {
+		if mark := r.refreshMarks[identifiable.Identity()]; mark != "" {
+			return mark
+		}
+	}
+
+	if trackable, ok := asTrackable(page); ok && r.tracker != nil {
+		if snooze, ok := r.tracker.Snoozed(trackable.URL(), trackable.Revision()); ok {
+			return snooze.String()
+		}
+
+		if !r.tracker.Seen(trackable.URL(), trackable.Revision()) {
+			return "unseen"
+		}
+	}
+
+	return ""
+}
+
+// trackShown records the entry that was shown as seen once
+// a different entry is shown, such as when moving to the next entry.
+func (r *Root) trackShown() {
+	var current pageset.Page
+	if ps := activePageSet(r.tabs); ps != nil {
+		current = ps.Current()
+	}
+
+	if current != r.shown {
+		if r.shown != nil {
+			r.markSeen(r.shown)
+		}
+		r.shown = current
+	}
+}
+
+func (r *Root) markSeen(page pageset.Page) {
+	trackable, ok := asTrackable(page)
+	if !ok || r.tracker == nil {
+		return
+	}
+
+	r.tracker.MarkSeen(trackable.URL(), trackable.Revision())
+	r.remark()
+}
+
+// markCurrentSeen records the entry that is currently shown as seen,
+// which is needed when quitting because it is never moved on from.
+func (r *Root) markCurrentSeen() {
+	if r.shown != nil {
+		r.markSeen(r.shown)
+	}
+}
+
+func (r *Root) markAllSeen() {
+	if r.tracker == nil {
+		return
+	}
+
+	for _, entry := range r.entries {
+		if trackable, ok := asTrackable(entry); ok {
+			r.tracker.MarkSeen(trackable.URL(), trackable.Revision())
+		}
+	}
+	r.remark()
+}
@@ -0,0 +1,138 @@
+package interactive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/printers/interactive/models/pageset"
+	"github.com/everettraven/wranglr/pkg/state"
+)
+
+// attachSnoozes adds the snooze actions to every page set and hides the
+// snoozed entries, which is needed whenever the page sets are created.
+func (r *Root) attachSnoozes() {
+	for _, ps := range pageSets(r.tabs) {
+		ps.ExtendActions(r.snoozeActions)
+	}
+	r.hideSnoozed()
+}
+
+// hideSnoozed hides the entries that are snoozed unless snoozed entries
+// are shown, which is needed whenever an entry may have been snoozed.
+func (r *Root) hideSnoozed() {
+	if r.tracker == nil || r.showSnoozed {
+		return
+	}
+
+	for _, ps := range pageSets(r.tabs) {
+		ps.Hide(r.snoozed)
+	}
+}
+
+func (r *Root) snoozed(page pageset.Page) bool {
+	trackable, ok := asTrackable(page)
+	if !ok {
+		return false
+	}
+
+	_, snoozed := r.tracker.Snoozed(trackable.URL(), trackable.Revision())
+	return snoozed
+}
+
+// snoozeActions returns the actions that snooze, dismiss or restore the entry.
+func (r *Root) snoozeActions(page pageset.Page) []pageset.Action {
+	trackable, ok := asTrackable(page)
+	if !ok || r.tracker == nil {
+		return nil
+	}
+
+	url, revision := trackable.URL(), trackable.Revision()
+	actions := []pageset.Action{
+		{
+			Name:  "snooze",
+			Key:   "z",
+			Input: "snooze until (YYYY-MM-DD, 3d, 2w or updated):",
+			Run: func(_ context.Context, input string) error {
+				snooze, err := parseSnooze(input, revision, time.Now())
+				if err != nil {
+					return err
+				}
+
+				r.tracker.Snooze(url, snooze)
+				return nil
+			},
+		},
+		{
+			Name: "dismiss",
+			Key:  "D",
+			Run: func(context.Context, string) error {
+				r.tracker.Snooze(url, state.Snooze{Dismissed: true})
+				return nil
+			},
+		},
+	}
+
+	// snoozed entries are only shown when showing snoozed entries
+	if _, snoozed := r.tracker.Snoozed(url, revision); snoozed {
+		actions = append(actions, pageset.Action{
+			Name: "restore",
+			Key:  "U",
+			Run: func(context.Context, string) error {
+				r.tracker.Restore(url)
+				return nil
+			},
+		})
+	}
+
+	return actions
+}
+
+// parseSnooze parses when a snooze ends, which is either a date (i.e
+// "2024-01-02"), a duration from now (i.e "3d", "2w" or "12h") or "updated"
+// for when the entry is next updated after it was last updated at revision.
+func parseSnooze(input string, revision, now time.Time) (state.Snooze, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "updated" {
+		return state.Snooze{UpdatedAt: revision}, nil
+	}
+
+	until, err := time.ParseInLocation(time.DateOnly, input, time.Local)
+	if err != nil {
+		duration, durationErr := parseDuration(input)
+		if durationErr != nil {
+			return state.Snooze{}, fmt.Errorf("%q is not a date (YYYY-MM-DD), a duration (i.e 3d) or \"updated\"", input)
+		}
+		until = now.Add(duration)
+	}
+
+	if !until.After(now) {
+		return state.Snooze{}, errors.New("the snooze must end in the future")
+	}
+
+	return state.Snooze{Until: until}, nil
+}
+
+// parseDuration parses a duration in days (i.e "3d"), weeks (i.e
+// "2w") or any unit accepted by time.ParseDuration (i.e "12h").
+func parseDuration(input string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	for suffix, unit := range units {
+		if number, ok := strings.CutSuffix(input, suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, err
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	return time.ParseDuration(input)
+}
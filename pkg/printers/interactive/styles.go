@@ -0,0 +1,10 @@
+package interactive
+
+import "github.com/charmbracelet/lipgloss/v2"
+
+var (
+	promptStyle = lipgloss.NewStyle().Margin(0, 0, 1, 2)
+	scopeStyle  = lipgloss.NewStyle().Faint(true)
+	noticeStyle = lipgloss.NewStyle().Faint(true)
+	failedStyle = lipgloss.NewStyle().Foreground(lipgloss.Red)
+)
@@ -3,21 +3,144 @@ package printers
 import (
 	"encoding/json"
 	"fmt"
-	"go.starlark.net/starlark"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/modules"
 )
 
-type JSON struct{}
+// SchemaVersion is the version of the schema used for
+// records output by the JSON and NDJSON printers.
+// It is bumped whenever a backwards incompatible change
+// is made to the schema.
+const SchemaVersion = "v1"
+
+// Record is the representation of an item in JSON output.
+type Record struct {
+	SchemaVersion string         `json:"schema_version"`
+	Source        string         `json:"source"`
+	ID            string         `json:"id"`
+	URL           string         `json:"url"`
+	Title         string         `json:"title"`
+	Group         string         `json:"group"`
+	Status        string         `json:"status"`
+	Priority      int64          `json:"priority"`
+	Stale         bool           `json:"stale"`
+	StaleSince    *time.Time     `json:"stale_since"`
+	Custom        map[string]any `json:"custom"`
+	Raw           any            `json:"raw"`
+}
+
+// NewRecord returns the JSON representation of an item.
+func NewRecord(item modules.Item) *Record {
+	record := &Record{
+		SchemaVersion: SchemaVersion,
+		Source:        item.Source(),
+		ID:            item.ID(),
+		URL:           item.URL(),
+		Title:         item.Title(),
+		Group:         item.Group(),
+		Status:        item.Status(),
+		Priority:      item.Priority(),
+		Custom:        map[string]any{},
+		Raw:           item.Raw(),
+	}
+
+	for name, val := range item.CustomFields() {
+		record.Custom[name] = modules.JSONValue(val)
+	}
+
+	if staleSince := item.StaleSince(); !staleSince.IsZero() {
+		record.Stale = true
+		record.StaleSince = &staleSince
+	}
+
+	return record
+}
+
+func newRecords(items ...modules.Item) []*Record {
+	records := []*Record{}
+	for _, item := range items {
+		records = append(records, NewRecord(item))
+	}
+	return records
+}
+
+// JSONIndentOption configures the number of spaces
+// used to indent the JSON output. Defaults to 2.
+// An indent of 0 outputs compact JSON.
+const JSONIndentOption = "indent"
+
+func jsonIndent(opts map[string]string) (int, error) {
+	indent, ok := opts[JSONIndentOption]
+	if !ok {
+		return 2, nil
+	}
+
+	spaces, err := strconv.Atoi(indent)
+	if err != nil || spaces < 0 {
+		return 0, fmt.Errorf("option %q must be a non-negative integer but was %q", JSONIndentOption, indent)
+	}
+
+	return spaces, nil
+}
+
+// JSON prints items as a JSON array of records. The items passed
+// to every call to Print are buffered and written as a single array
+// by Flush, so that the output is always a single JSON document.
+type JSON struct {
+	// Indent is the number of spaces used for indentation.
+	Indent int
+
+	// Out is where the records are written. Defaults to os.Stdout.
+	Out io.Writer
+
+	records []*Record
+}
 
-func (j *JSON) Print(results ...starlark.Value) error {
-	outBytes := []byte{}
-	for _, result := range results {
-		out, err := json.Marshal(result)
-		if err != nil {
+func (j *JSON) Print(items ...modules.Item) error {
+	j.records = append(j.records, newRecords(items...)...)
+	return nil
+}
+
+// Flush writes the records of every item that has been printed.
+func (j *JSON) Flush() error {
+	records := j.records
+	if records == nil {
+		records = []*Record{}
+	}
+	j.records = nil
+
+	encoder := json.NewEncoder(output(j.Out))
+	encoder.SetIndent("", strings.Repeat(" ", j.Indent))
+	return encoder.Encode(records)
+}
+
+// NDJSON prints items as newline delimited JSON records.
+type NDJSON struct {
+	// Out is where the records are written. Defaults to os.Stdout.
+	Out io.Writer
+}
+
+func (n *NDJSON) Print(items ...modules.Item) error {
+	records := newRecords(items...)
+
+	encoder := json.NewEncoder(output(n.Out))
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
 			return err
 		}
-		outBytes = append(outBytes, out...)
 	}
 
-	fmt.Println(string(outBytes))
 	return nil
 }
+
+func output(out io.Writer) io.Writer {
+	if out == nil {
+		return os.Stdout
+	}
+	return out
+}
@@ -0,0 +1,150 @@
+package printers
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/search"
+	"go.starlark.net/starlark"
+
+	"github.com/everettraven/wranglr/pkg/modules"
+	"github.com/everettraven/wranglr/pkg/modules/github"
+	"github.com/everettraven/wranglr/pkg/modules/jira"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+const githubIssue = `{
+	"node_id": "I_kwDOAbc123",
+	"number": 42,
+	"title": "Crash when the config is empty",
+	"body": "Running wranglr with an empty config panics.",
+	"html_url": "https://github.com/everettraven/wranglr/issues/42",
+	"repository_url": "https://api.github.com/repos/everettraven/wranglr",
+	"state": "open",
+	"user": {"login": "octocat"},
+	"assignees": [{"login": "hubot"}],
+	"labels": [{"name": "bug", "color": "d73a4a"}],
+	"comments": 3,
+	"created_at": "2024-01-02T03:04:05Z",
+	"updated_at": "2024-01-03T03:04:05Z"
+}`
+
+const jiraIssue = `{
+	"id": "10001",
+	"key": "WR-7",
+	"self": "https://jira.example.com/rest/api/2/issue/10001",
+	"fields": {
+		"summary": "Support Jira filters",
+		"description": "Allow searching using a saved filter.",
+		"status": {"name": "In Progress"},
+		"labels": ["feature"]
+	}
+}`
+
+func testItems(t *testing.T) []modules.Item {
+	t.Helper()
+
+	var ghIssue search.Issue
+	if err := json.Unmarshal([]byte(githubIssue), &ghIssue); err != nil {
+		t.Fatalf("decoding GitHub issue: %v", err)
+	}
+	ghItem := github.NewItem(ghIssue, "bugs", time.Time{})
+	if err := ghItem.SetField(modules.StatusAttr, starlark.String("triage")); err != nil {
+		t.Fatalf("setting status: %v", err)
+	}
+	if err := ghItem.SetField(modules.PriorityAttr, starlark.MakeInt(1)); err != nil {
+		t.Fatalf("setting priority: %v", err)
+	}
+	if err := ghItem.SetField("team", starlark.String("core")); err != nil {
+		t.Fatalf("setting custom field: %v", err)
+	}
+
+	var jIssue jira.Issue
+	if err := json.Unmarshal([]byte(jiraIssue), &jIssue); err != nil {
+		t.Fatalf("decoding Jira issue: %v", err)
+	}
+	staleSince := time.Date(2024, time.February, 1, 12, 0, 0, 0, time.UTC)
+	jItem := jira.NewItem(jIssue, "https://jira.example.com/browse/WR-7", "features", staleSince)
+
+	return []modules.Item{ghItem, jItem}
+}
+
+func TestJSONPrinters(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		printer func(out *bytes.Buffer) Printer
+	}{
+		{
+			name:    "json",
+			printer: func(out *bytes.Buffer) Printer { return &JSON{Indent: 2, Out: out} },
+		},
+		{
+			name:    "ndjson",
+			printer: func(out *bytes.Buffer) Printer { return &NDJSON{Out: out} },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			printer := tc.printer(out)
+
+			// each item is rendered separately to check that
+			// multiple renders produce a single document
+			for _, item := range testItems(t) {
+				if err := printer.Print(item); err != nil {
+					t.Fatalf("printing: %v", err)
+				}
+			}
+			if flusher, ok := printer.(Flusher); ok {
+				if err := flusher.Flush(); err != nil {
+					t.Fatalf("flushing: %v", err)
+				}
+			}
+
+			assertGolden(t, filepath.Join("testdata", tc.name+".golden"), out.Bytes())
+		})
+	}
+}
+
+func TestJSONFlushWithoutItems(t *testing.T) {
+	out := &bytes.Buffer{}
+	printer := &JSON{Out: out}
+	if err := printer.Flush(); err != nil {
+		t.Fatalf("flushing: %v", err)
+	}
+
+	if got, want := out.String(), "[]\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestJSONRecordSchemaVersion(t *testing.T) {
+	for _, item := range testItems(t) {
+		if got := NewRecord(item).SchemaVersion; got != "v1" {
+			t.Errorf("%s: got schema version %q, want %q", item.ID(), got, "v1")
+		}
+	}
+}
+
+func assertGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("updating golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output doesn't match %s (run with -update to update it):\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,32 @@
+package printers
+
+import (
+	"context"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+// Refresher re-executes the configuration, returning the items
+// passed to each of its calls to wranglr.render(...) in order, and
+// any warnings, such as stale cached responses being used, which
+// the printer shows in place of them being printed to stderr.
+type Refresher func(ctx context.Context) (renders [][]modules.Item, warnings []string, err error)
+
+// Refreshable is implemented by printers that can refresh the items
+// they print, such as the interactive printer which stays open until
+// the user quits it.
+type Refreshable interface {
+	// SetRefresher sets the Refresher used to refresh the printed items,
+	// and the interval they are automatically refreshed at. Items are
+	// only automatically refreshed when the interval is greater than 0.
+	SetRefresher(refresh Refresher, interval time.Duration)
+}
+
+// Cancellable is implemented by printers that keep running after
+// the items are printed, such as the interactive printer, which use
+// the context for the work they do in the background so that it is
+// cancelled along with the run (i.e when the user hits Ctrl+C).
+type Cancellable interface {
+	SetContext(ctx context.Context)
+}
@@ -0,0 +1,115 @@
+package printers
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/everettraven/wranglr/pkg/modules"
+)
+
+// Printer prints items in a particular output format.
+type Printer interface {
+	Print(items ...modules.Item) error
+}
+
+// Flusher is implemented by printers that buffer the items passed to
+// Print, such as the JSON printer, and write them once the configuration
+// has finished executing.
+type Flusher interface {
+	Flush() error
+}
+
+// Factory creates a Printer configured using printer-specific
+// options. Factories should return an error for options they
+// don't support.
+type Factory func(opts map[string]string) (Printer, error)
+
+var printers = map[string]Factory{}
+
+func Register(name string, factory Factory) error {
+	if _, ok := printers[name]; ok {
+		return fmt.Errorf("printer %q is already registered", name)
+	}
+
+	printers[name] = factory
+
+	return nil
+}
+
+// MustRegister is like Register but panics if the printer
+// can't be registered.
+func MustRegister(name string, factory Factory) {
+	if err := Register(name, factory); err != nil {
+		panic(err)
+	}
+}
+
+// New creates the printer registered with the provided name.
+func New(name string, opts map[string]string) (Printer, error) {
+	factory, ok := printers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q, must be one of [%s]", name, strings.Join(Names(), ", "))
+	}
+
+	printer, err := factory(opts)
+	if err != nil {
+		return nil, fmt.Errorf("configuring %q output: %w", name, err)
+	}
+
+	return printer, nil
+}
+
+// Names returns the sorted names of all registered printers.
+func Names() []string {
+	names := []string{}
+	for name := range printers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateOptions returns an error if opts contains any
+// options that aren't in the list of supported options.
+func validateOptions(opts map[string]string, supported ...string) error {
+	for key := range opts {
+		if !slices.Contains(supported, key) {
+			if len(supported) == 0 {
+				return fmt.Errorf("unknown option %q, no options are supported", key)
+			}
+			return fmt.Errorf("unknown option %q, must be one of [%s]", key, strings.Join(supported, ", "))
+		}
+	}
+	return nil
+}
+
+func init() {
+	MustRegister("interactive", func(opts map[string]string) (Printer, error) {
+		if err := validateOptions(opts); err != nil {
+			return nil, err
+		}
+		return &Interactive{}, nil
+	})
+
+	MustRegister("json", func(opts map[string]string) (Printer, error) {
+		if err := validateOptions(opts, JSONIndentOption); err != nil {
+			return nil, err
+		}
+
+		indent, err := jsonIndent(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &JSON{Indent: indent}, nil
+	})
+
+	MustRegister("ndjson", func(opts map[string]string) (Printer, error) {
+		if err := validateOptions(opts); err != nil {
+			return nil, err
+		}
+		return &NDJSON{}, nil
+	})
+}
@@ -0,0 +1,25 @@
+package printers
+
+import (
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/state"
+)
+
+// Tracker records which items have been seen or snoozed.
+type Tracker interface {
+	Seen(url string, updatedAt time.Time) bool
+	MarkSeen(url string, updatedAt time.Time)
+
+	Snoozed(url string, updatedAt time.Time) (state.Snooze, bool)
+	Snooze(url string, snooze state.Snooze)
+	Restore(url string)
+}
+
+// Trackable is implemented by printers that show items to the user
+// and can record which of them have been seen or snoozed, such as the
+// interactive printer. Snoozed items are hidden once snoozed unless
+// showSnoozed is true.
+type Trackable interface {
+	SetTracker(tracker Tracker, showSnoozed bool)
+}
@@ -0,0 +1,272 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// DefaultLibPath returns the directory that is always searched last for
+// modules loaded with load(...), which is ~/.config/wranglr/lib. It is
+// empty if the home directory can't be determined.
+func DefaultLibPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(homeDir, ".config", "wranglr", "lib")
+}
+
+// libraries configures where modules loaded with load(...) are found.
+type libraries struct {
+	paths []string
+	repos map[string]string
+}
+
+func (o *Options) libraries() libraries {
+	libs := libraries{
+		paths: o.LibPaths,
+		repos: o.LibRepos,
+	}
+
+	if dir := DefaultLibPath(); dir != "" {
+		libs.paths = append(slices.Clone(libs.paths), dir)
+	}
+
+	return libs
+}
+
+const locationLocal = "wranglr.location"
+
+// location is where a Starlark file is loaded from, which is either a path on
+// the filesystem or a path within a library repository, at a ref or within its
+// working tree.
+type location struct {
+	// repo is the name of the library repository,
+	// or empty if the file is on the filesystem.
+	repo string
+
+	// ref is the git ref the file is read at, or
+	// empty to read it from the working tree.
+	ref string
+
+	// path is the path of the file on the filesystem, or
+	// the slash separated path within the repository.
+	path string
+}
+
+// String returns the location in the form used by load(...),
+// which also identifies it in error messages and the cache.
+func (l location) String() string {
+	switch {
+	case l.repo == "":
+		return l.path
+	case l.ref == "":
+		return "@" + l.repo + "//" + l.path
+	default:
+		return "@" + l.repo + "@" + l.ref + "//" + l.path
+	}
+}
+
+// loader implements load(...) for a single execution of the configuration.
+// Each module is executed once, and the globals it defines are shared by
+// every file that loads it.
+type loader struct {
+	ctx       context.Context
+	options   *syntax.FileOptions
+	globals   starlark.StringDict
+	newThread func(name string) *starlark.Thread
+
+	// paths are the directories searched for modules that aren't
+	// found relative to the loading file, and repos are the local
+	// git checkouts modules can be loaded from by name.
+	paths []string
+	repos map[string]string
+
+	cache map[string]*loadResult
+
+	// loading is the chain of modules being loaded,
+	// which is used to report cycles.
+	loading []string
+}
+
+type loadResult struct {
+	globals starlark.StringDict
+	err     error
+	done    bool
+}
+
+// load is the starlark.Thread Load function.
+func (l *loader) load(thread *starlark.Thread, module string) (starlark.StringDict, error) {
+	from, _ := thread.Local(locationLocal).(location)
+
+	loc, err := l.resolve(from, module)
+	if err != nil {
+		return nil, err
+	}
+
+	key := loc.String()
+	if result, ok := l.cache[key]; ok {
+		if !result.done {
+			return nil, fmt.Errorf("cycle in load graph: %s -> %s", strings.Join(l.loading, " -> "), key)
+		}
+		return result.globals, result.err
+	}
+
+	result := &loadResult{}
+	l.cache[key] = result
+	l.loading = append(l.loading, key)
+	defer func() { l.loading = l.loading[:len(l.loading)-1] }()
+
+	result.globals, result.err = l.exec(loc)
+	result.done = true
+	return result.globals, result.err
+}
+
+// exec executes the file at loc on a new thread, returning the globals it defines.
+func (l *loader) exec(loc location) (starlark.StringDict, error) {
+	src, err := l.read(loc)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := l.newThread(loc.String())
+	thread.Load = l.load
+	thread.SetLocal(locationLocal, loc)
+
+	stop := context.AfterFunc(l.ctx, func() {
+		thread.Cancel(context.Cause(l.ctx).Error())
+	})
+	defer stop()
+
+	globals, err := starlark.ExecFileOptions(l.options, thread, loc.String(), src, l.globals)
+	if err != nil {
+		return nil, err
+	}
+
+	globals.Freeze()
+	return globals, nil
+}
+
+// resolve resolves the module passed to load(...) by the file at from.
+// Modules of the form "@name//path" or "@name@ref//path" are loaded from a
+// library repository. Other modules are relative to the loading file and, if
+// the loading file is on the filesystem and the module isn't found next to
+// it, are searched for in the library path.
+func (l *loader) resolve(from location, module string) (location, error) {
+	if rest, ok := strings.CutPrefix(module, "@"); ok {
+		repo, file, ok := strings.Cut(rest, "//")
+		if !ok {
+			return location{}, errors.New("modules in library repositories must be of the form \"@name//path\" or \"@name@ref//path\"")
+		}
+
+		repo, ref, _ := strings.Cut(repo, "@")
+
+		// refs are passed to git, which would treat them as options
+		if strings.HasPrefix(ref, "-") {
+			return location{}, fmt.Errorf("invalid ref %q: refs can't start with \"-\"", ref)
+		}
+
+		return l.resolveInRepo(location{repo: repo, ref: ref}, file)
+	}
+
+	if from.repo != "" {
+		return l.resolveInRepo(from, path.Join(path.Dir(from.path), module))
+	}
+
+	candidates := []string{module}
+	if !filepath.IsAbs(module) {
+		candidates = []string{filepath.Join(filepath.Dir(from.path), module)}
+		for _, dir := range l.paths {
+			candidates = append(candidates, filepath.Join(dir, module))
+		}
+	}
+
+	for _, candidate := range candidates {
+		_, err := os.Stat(candidate)
+		if err == nil {
+			// the same file is cached once however it is loaded
+			abs, err := filepath.Abs(candidate)
+			if err != nil {
+				return location{}, err
+			}
+			return location{path: abs}, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return location{}, err
+		}
+	}
+
+	return location{}, fmt.Errorf("not found relative to %s or in the library path [%s]", from, strings.Join(l.paths, ", "))
+}
+
+// resolveInRepo resolves the file within the repository of
+// repo, which must not be outside of the repository.
+func (l *loader) resolveInRepo(repo location, file string) (location, error) {
+	if _, ok := l.repos[repo.repo]; !ok {
+		return location{}, fmt.Errorf("unknown library repository %q. Library repositories are configured using --lib-repo name=dir", repo.repo)
+	}
+
+	file = path.Clean(file)
+	if file == ".." || strings.HasPrefix(file, "../") || path.IsAbs(file) {
+		return location{}, fmt.Errorf("path must be within the library repository %q", repo.repo)
+	}
+
+	repo.path = file
+	return repo, nil
+}
+
+// read reads the file at loc. Files in a library repository
+// at a ref are read using git, so git must be installed.
+func (l *loader) read(loc location) ([]byte, error) {
+	switch {
+	case loc.repo == "":
+		return os.ReadFile(loc.path)
+	case loc.ref == "":
+		return os.ReadFile(filepath.Join(l.repos[loc.repo], filepath.FromSlash(loc.path)))
+	}
+
+	// the ref is resolved to a commit first so that only
+	// the commit, and never the ref itself, is passed to git show
+	commit, err := l.git(loc.repo, "rev-parse", "--verify", "--quiet", "--end-of-options", loc.ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("resolving ref %q: %w", loc.ref, err)
+	}
+
+	src, err := l.git(loc.repo, "show", strings.TrimSpace(string(commit))+":"+loc.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s at %s: %w", loc.path, loc.ref, err)
+	}
+
+	return src, nil
+}
+
+// git runs git in the checkout of the library repository,
+// returning its output or an error including its stderr.
+func (l *loader) git(repo string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(l.ctx, "git", append([]string{"-C", l.repos[repo]}, args...)...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return out, nil
+}
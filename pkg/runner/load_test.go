@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// newLibRepo creates a git repository with a single commit
+// containing lib.star, which defines name as "committed".
+func newLibRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+
+	git("init", "-q")
+	if err := os.WriteFile(filepath.Join(dir, "lib.star"), []byte(`name = "committed"`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "lib.star")
+	git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "lib")
+
+	return dir
+}
+
+func loadFrom(t *testing.T, repo, module string) (starlark.StringDict, error) {
+	t.Helper()
+
+	l := &loader{
+		ctx:       context.Background(),
+		options:   &syntax.FileOptions{},
+		globals:   starlark.StringDict{},
+		newThread: func(name string) *starlark.Thread { return &starlark.Thread{Name: name} },
+		repos:     map[string]string{"lib": repo},
+		cache:     map[string]*loadResult{},
+	}
+
+	thread := &starlark.Thread{Name: "main"}
+	thread.SetLocal(locationLocal, location{path: filepath.Join(t.TempDir(), "config.star")})
+	return l.load(thread, module)
+}
+
+func TestLoadAtRef(t *testing.T) {
+	repo := newLibRepo(t)
+
+	globals, err := loadFrom(t, repo, "@lib@HEAD//lib.star")
+	if err != nil {
+		t.Fatalf("loading at HEAD: %v", err)
+	}
+	if got := globals["name"]; got != starlark.String("committed") {
+		t.Errorf("name = %v, want %q", got, "committed")
+	}
+
+	_, err = loadFrom(t, repo, "@lib@missing//lib.star")
+	if err == nil || !strings.Contains(err.Error(), `resolving ref "missing"`) {
+		t.Errorf("loading at a missing ref: got error %v", err)
+	}
+}
+
+func TestLoadRejectsOptionRefs(t *testing.T) {
+	repo := newLibRepo(t)
+	output := filepath.Join(t.TempDir(), "written")
+
+	for _, ref := range []string{"--output=" + output, "-p"} {
+		_, err := loadFrom(t, repo, "@lib@"+ref+"//lib.star")
+		if err == nil || !strings.Contains(err.Error(), "refs can't start with") {
+			t.Errorf("loading at %q: got error %v", ref, err)
+		}
+	}
+
+	if _, err := os.Stat(output); !os.IsNotExist(err) {
+		t.Errorf("git wrote %s", output)
+	}
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/everettraven/wranglr/pkg/modules"
+	"github.com/everettraven/wranglr/pkg/state"
+)
+
+// printPlan prints the changes requested by
+// the configuration without applying them.
+func printPlan(w io.Writer, changes []*modules.Change) {
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "The configuration requested %d change(s):\n", len(changes))
+	for _, change := range changes {
+		fmt.Fprintf(w, "  %s\n", describeChange(change))
+	}
+	fmt.Fprintln(w, "Run with --apply to make these changes.")
+}
+
+// applyPlan applies the changes requested by the configuration,
+// recording each of them in the audit log. All changes are
+// attempted even if some of them fail.
+func applyPlan(ctx context.Context, w io.Writer, changes []*modules.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	auditPath, err := AuditLogPath()
+	if err != nil {
+		return err
+	}
+
+	// the audit log is opened before applying any changes
+	// so that no changes are made without being recorded
+	audit, err := openAuditLog(auditPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = audit.Close() }()
+
+	failed := 0
+	for _, change := range changes {
+		applyErr := change.Apply(ctx)
+
+		entry := auditEntry{
+			Time:   time.Now().UTC(),
+			Source: change.Item.Source(),
+			Item:   change.Item.ID(),
+			URL:    change.Item.URL(),
+			Change: change.Description,
+		}
+
+		if applyErr != nil {
+			failed++
+			entry.Error = applyErr.Error()
+			fmt.Fprintf(w, "failed: %s: %v\n", describeChange(change), applyErr)
+		} else {
+			fmt.Fprintf(w, "applied: %s\n", describeChange(change))
+		}
+
+		if err := json.NewEncoder(audit).Encode(entry); err != nil {
+			return fmt.Errorf("writing audit log %q: %w", auditPath, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d change(s) failed, see the audit log %q", failed, len(changes), auditPath)
+	}
+
+	return nil
+}
+
+func describeChange(change *modules.Change) string {
+	return fmt.Sprintf("%s %s: %s", change.Item.Source(), change.Item.ID(), change.Description)
+}
+
+type auditEntry struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Item   string    `json:"item"`
+	URL    string    `json:"url"`
+	Change string    `json:"change"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// AuditLogPath returns the path of the audit log that changes applied
+// using --apply are recorded in, which is audit.log in the state directory.
+func AuditLogPath() (string, error) {
+	dir, err := state.Dir()
+	if err != nil {
+		return "", fmt.Errorf("determining audit log path: %w", err)
+	}
+
+	return filepath.Join(dir, "audit.log"), nil
+}
+
+func openAuditLog(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,168 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/search"
+
+	"github.com/everettraven/wranglr/pkg/modules"
+	"github.com/everettraven/wranglr/pkg/modules/github"
+)
+
+// changeServer records the paths of the requests made by the changes
+// from newChanges, failing requests to paths ending in /fail.
+type changeServer struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	paths []string
+}
+
+func newChangeServer(t *testing.T) *changeServer {
+	t.Helper()
+
+	server := &changeServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		server.paths = append(server.paths, r.URL.Path)
+		server.mu.Unlock()
+
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			http.Error(w, "failed", http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (s *changeServer) requested() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.paths...)
+}
+
+// newChanges returns a change for each path that requests the path from the server.
+func newChanges(server *changeServer, paths ...string) []*modules.Change {
+	changes := []*modules.Change{}
+	for i, path := range paths {
+		item := github.NewItem(search.Issue{
+			Number:        i + 1,
+			RepositoryURL: "https://api.github.com/repos/octo/repo",
+			URL:           fmt.Sprintf("https://github.com/octo/repo/issues/%d", i+1),
+		}, "", time.Time{})
+
+		changes = append(changes, modules.NewChange(item, "request "+path, func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+path, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status %s", resp.Status)
+			}
+			return nil
+		}))
+	}
+	return changes
+}
+
+func TestPrintPlan(t *testing.T) {
+	server := newChangeServer(t)
+
+	out := &bytes.Buffer{}
+	printPlan(out, newChanges(server, "/first", "/second"))
+
+	if requested := server.requested(); len(requested) != 0 {
+		t.Errorf("expected no requests printing the plan, got %v", requested)
+	}
+
+	want := "The configuration requested 2 change(s):\n" +
+		"  github octo/repo#1: request /first\n" +
+		"  github octo/repo#2: request /second\n" +
+		"Run with --apply to make these changes.\n"
+	if got := out.String(); got != want {
+		t.Errorf("got output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestApplyPlan(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	server := newChangeServer(t)
+
+	out := &bytes.Buffer{}
+	err := applyPlan(context.Background(), out, newChanges(server, "/first", "/fail", "/third"))
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 change(s) failed") {
+		t.Errorf("got error %v, want 1 of the 3 changes to fail", err)
+	}
+
+	// the failed change doesn't stop the changes after it
+	want := []string{"/first", "/fail", "/third"}
+	if requested := server.requested(); strings.Join(requested, " ") != strings.Join(want, " ") {
+		t.Errorf("got requests %v, want %v", requested, want)
+	}
+
+	auditPath, err := AuditLogPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(auditPath)
+	if err != nil {
+		t.Fatalf("opening audit log: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	entries := []auditEntry{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		entry := auditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("decoding audit log entry %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("got %d audit log entries, want one per change: %+v", len(entries), entries)
+	}
+	for i, entry := range entries {
+		if entry.Source != "github" || entry.Item != fmt.Sprintf("octo/repo#%d", i+1) || entry.Change != "request "+want[i] {
+			t.Errorf("got audit log entry %+v for the change requesting %s", entry, want[i])
+		}
+		if failed := entry.Error != ""; failed != (want[i] == "/fail") {
+			t.Errorf("got audit log entry error %q for the change requesting %s", entry.Error, want[i])
+		}
+	}
+}
+
+func TestApplyEmptyPlan(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if err := applyPlan(context.Background(), &bytes.Buffer{}, nil); err != nil {
+		t.Fatalf("applying an empty plan: %v", err)
+	}
+
+	auditPath, err := AuditLogPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(auditPath); !os.IsNotExist(err) {
+		t.Errorf("expected no audit log without any changes, got %v", err)
+	}
+}
@@ -5,62 +5,267 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
-	"go.starlark.net/lib/time"
+	startime "go.starlark.net/lib/time"
 	"go.starlark.net/starlark"
 	"go.starlark.net/syntax"
 
+	"github.com/everettraven/wranglr/pkg/httpcache"
 	"github.com/everettraven/wranglr/pkg/modules"
 	"github.com/everettraven/wranglr/pkg/modules/github"
+	"github.com/everettraven/wranglr/pkg/modules/gitlab"
+	"github.com/everettraven/wranglr/pkg/modules/http"
 	"github.com/everettraven/wranglr/pkg/modules/jira"
 	"github.com/everettraven/wranglr/pkg/modules/wranglr"
+	"github.com/everettraven/wranglr/pkg/printers"
+	"github.com/everettraven/wranglr/pkg/state"
 )
 
+// DefaultConcurrency is the default maximum number of
+// asynchronous fetches that may run at the same time.
+const DefaultConcurrency = 4
+
 type Options struct {
-	ConfigFile   string
-	OutputFormat string
+	ConfigFile    string
+	OutputFormat  string
+	OutputOptions map[string]string
+	Concurrency   int
+	CacheTTL      time.Duration
+	NoCache       bool
+	Offline       bool
+
+	// RefreshInterval is the interval that printers which stay open,
+	// such as the interactive printer, refresh their items at by
+	// executing the configuration again. 0 disables refreshing
+	// automatically.
+	RefreshInterval time.Duration
+
+	// ShowSnoozed renders items that were snoozed or dismissed
+	// in the interactive output, which aren't rendered by default.
+	ShowSnoozed bool
+
+	// LibPaths are directories searched, in order, for modules loaded
+	// with load(...) that aren't found relative to the loading file.
+	// DefaultLibPath is always searched last.
+	LibPaths []string
+
+	// LibRepos are local git checkouts, by name, that modules can be
+	// loaded from using load("@name//path.star", ...), or
+	// load("@name@ref//path.star", ...) to load them at a git ref.
+	LibRepos map[string]string
+
+	// Apply applies the changes requested by mutating builtins
+	// (i.e github.add_labels(...)). When false, the changes
+	// are only printed.
+	Apply bool
 }
 
 func (o *Options) Run(ctx context.Context) error {
+	printer, err := printers.New(o.OutputFormat, o.OutputOptions)
+	if err != nil {
+		return err
+	}
+
+	cache := httpcache.New(httpcache.Options{
+		TTL:      o.CacheTTL,
+		Disabled: o.NoCache,
+		Offline:  o.Offline,
+		Warn: func(msg string) {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+		},
+	})
+
 	// register all modules
-	err := modules.Register(github.New())
+	err = modules.Register(github.New(cache))
+	if err != nil {
+		return err
+	}
+
+	err = modules.Register(jira.New(cache))
 	if err != nil {
 		return err
 	}
 
-	err = modules.Register(jira.New())
+	err = modules.Register(gitlab.New(cache))
 	if err != nil {
 		return err
 	}
 
-	err = modules.Register(wranglr.New(o.OutputFormat))
+	err = modules.Register(http.New(cache))
 	if err != nil {
 		return err
 	}
 
+	store := loadStore()
+
+	if cancellable, ok := printer.(printers.Cancellable); ok {
+		cancellable.SetContext(ctx)
+	}
+
+	if refreshable, ok := printer.(printers.Refreshable); ok {
+		refreshable.SetRefresher(o.refresher(store), o.RefreshInterval)
+	}
+
+	if trackable, ok := printer.(printers.Trackable); ok && store != nil {
+		trackable.SetTracker(store, o.ShowSnoozed)
+	}
+
 	// Do actual things
-	_, err = configureThread(o.ConfigFile, o.OutputFormat)
+	plan := &modules.Plan{}
+	_, err = configureThread(ctx, o.ConfigFile, o.Concurrency, plan, printer, store, wranglr.Options{
+		ShowSnoozed: o.ShowSnoozed,
+	}, o.libraries())
+
+	// items may have been seen even if the configuration failed
+	// after they were rendered, so the store is always saved
+	if store != nil {
+		if saveErr := store.Save(); saveErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", saveErr)
+		}
+	}
+
+	// items rendered before the configuration failed are still written
+	if flusher, ok := printer.(printers.Flusher); ok {
+		if flushErr := flusher.Flush(); flushErr != nil && err == nil {
+			return fmt.Errorf("writing output: %w", flushErr)
+		}
+	}
+
 	if err != nil {
 		return fmt.Errorf("configuring thread: %w", err)
 	}
+
+	// changes are reported on stderr so they
+	// don't interfere with the rendered output
+	if !o.Apply {
+		printPlan(os.Stderr, plan.Changes())
+		return nil
+	}
+
+	return applyPlan(ctx, os.Stderr, plan.Changes())
+}
+
+// loadStore loads the store that records which items have been seen or snoozed.
+// Failing to load it isn't fatal, so a warning is printed and nil is
+// returned instead, in which case items aren't tracked.
+func loadStore() *state.Store {
+	path, err := state.DefaultPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: not tracking seen or snoozed items: %v\n", err)
+		return nil
+	}
+
+	store, err := state.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: not tracking seen or snoozed items: %v\n", err)
+		return nil
+	}
+
+	return store
+}
+
+// refresher returns a Refresher that executes the configuration again,
+// collecting the items it renders instead of printing them. Changes
+// requested by mutating builtins while refreshing are ignored. Warnings
+// from the cache are returned instead of being printed, as the printer
+// being refreshed may be using the terminal.
+func (o *Options) refresher(store *state.Store) printers.Refresher {
+	return func(ctx context.Context) ([][]modules.Item, []string, error) {
+		collector := &collector{}
+		ctx = httpcache.WithWarn(ctx, collector.warn)
+
+		_, err := configureThread(ctx, o.ConfigFile, o.Concurrency, &modules.Plan{}, collector, store, wranglr.Options{
+			ShowSnoozed: o.ShowSnoozed,
+		}, o.libraries())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		collector.mu.Lock()
+		defer collector.mu.Unlock()
+		return collector.renders, collector.warnings, nil
+	}
+}
+
+// collector is a printer that collects the items passed
+// to each call to wranglr.render(...) instead of printing them.
+type collector struct {
+	renders [][]modules.Item
+
+	// warnings may be collected from fetches running in
+	// the background, so they are guarded by mu
+	mu       sync.Mutex
+	warnings []string
+}
+
+func (c *collector) warn(msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.warnings = append(c.warnings, msg)
+}
+
+func (c *collector) Print(items ...modules.Item) error {
+	c.renders = append(c.renders, items)
 	return nil
 }
 
-func configureThread(configFile string, output string) (*starlark.Thread, error) {
+func configureThread(ctx context.Context, configFile string, concurrency int, plan *modules.Plan, printer printers.Printer, store *state.Store, opts wranglr.Options, libs libraries) (*starlark.Thread, error) {
 	globals := starlark.StringDict{}
-	starlark.Universe["time"] = time.Module
+	starlark.Universe["time"] = startime.Module
 
 	for name, module := range modules.Modules() {
 		globals[name] = module
 	}
 
-	thread := &starlark.Thread{Name: "main"}
+	// the wranglr module renders items using the printer
+	// for the thread, so it isn't registered with the others
+	name, module := wranglr.New(printer, opts)
+	globals[name] = module
+
+	fileOptions := &syntax.FileOptions{
+		TopLevelControl: true,
+		GlobalReassign:  true,
+		Set:             true,
+	}
+
+	// modules loaded with load(...) are executed on threads of their
+	// own, which need the same state as the main thread
+	newThread := func(name string) *starlark.Thread {
+		thread := &starlark.Thread{Name: name}
+		modules.SetContext(thread, ctx)
+		modules.SetConcurrency(thread, concurrency)
+		modules.SetPlan(thread, plan)
+		if store != nil {
+			modules.SetItemState(thread, store)
+		}
+		return thread
+	}
+
+	loader := &loader{
+		ctx:       ctx,
+		options:   fileOptions,
+		globals:   globals,
+		newThread: newThread,
+		paths:     libs.paths,
+		repos:     libs.repos,
+		cache:     map[string]*loadResult{},
+	}
+
+	thread := newThread("main")
+	thread.Load = loader.load
+	thread.SetLocal(locationLocal, location{path: configFile})
+
+	// stop executing the configuration as soon as the context
+	// is cancelled, i.e when the user hits Ctrl+C.
+	stop := context.AfterFunc(ctx, func() {
+		thread.Cancel(context.Cause(ctx).Error())
+	})
+	defer stop()
 
 	_, err := starlark.ExecFileOptions(
-		&syntax.FileOptions{
-			TopLevelControl: true,
-			GlobalReassign:  true,
-		},
+		fileOptions,
 		thread,
 		configFile,
 		nil,
@@ -0,0 +1,288 @@
+// Package state implements a local store for state about items that
+// is kept between runs, such as which items have been seen or snoozed.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// retention is how long an item is remembered after it was last
+// seen or snoozed until it is updated. Items that haven't been seen
+// for longer are forgotten when the store is saved so that the
+// store doesn't grow forever. Dismissed items are never forgotten.
+const retention = 180 * 24 * time.Hour
+
+// Dir returns the directory local state is stored in, which is
+// $XDG_STATE_HOME/wranglr or ~/.local/state/wranglr if
+// $XDG_STATE_HOME is not set.
+func Dir() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("determining state directory: %w", err)
+		}
+		stateDir = filepath.Join(homeDir, ".local", "state")
+	}
+
+	return filepath.Join(stateDir, "wranglr"), nil
+}
+
+// DefaultPath returns the default path of the store.
+func DefaultPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// Store records state about items, keyed by their URL.
+// It is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu    sync.Mutex
+	items map[string]*itemState
+	dirty bool
+}
+
+type itemState struct {
+	// UpdatedAt is when the item was last updated
+	// at the time it was last seen.
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+
+	// SeenAt is when the item was last seen, or
+	// the zero time if it has never been seen.
+	SeenAt time.Time `json:"seen_at,omitzero"`
+
+	Snooze *Snooze `json:"snooze,omitempty"`
+}
+
+// Snooze hides an item until a time, until it is updated
+// or, if it was dismissed, until it is restored.
+type Snooze struct {
+	// Until is when the snooze ends, or the zero time if the
+	// snooze ends when the item is updated or it was dismissed.
+	Until time.Time `json:"until,omitzero"`
+
+	// UpdatedAt is when the item was last updated at the time it
+	// was snoozed if the snooze ends when the item is updated,
+	// or the zero time otherwise.
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+
+	// Dismissed is whether the item was dismissed.
+	Dismissed bool `json:"dismissed,omitempty"`
+
+	// At is when the item was snoozed.
+	At time.Time `json:"at"`
+}
+
+// String describes the snooze (i.e "snoozed until 2024-01-02").
+func (s Snooze) String() string {
+	switch {
+	case s.Dismissed:
+		return "dismissed"
+	case !s.Until.IsZero():
+		return "snoozed until " + s.Until.Local().Format(time.DateOnly)
+	default:
+		return "snoozed until updated"
+	}
+}
+
+// active returns whether the snooze still hides
+// an item that was last updated at updatedAt.
+func (s Snooze) active(updatedAt time.Time) bool {
+	switch {
+	case s.Dismissed:
+		return true
+	case !s.Until.IsZero():
+		return time.Now().Before(s.Until)
+	default:
+		return !updatedAt.After(s.UpdatedAt)
+	}
+}
+
+// expired returns whether the snooze can be forgotten because it
+// no longer hides the item, or might not and is too old to keep.
+func (s Snooze) expired() bool {
+	switch {
+	case s.Dismissed:
+		return false
+	case !s.Until.IsZero():
+		return !time.Now().Before(s.Until)
+	default:
+		return time.Since(s.At) > retention
+	}
+}
+
+type file struct {
+	Items map[string]*itemState `json:"items"`
+}
+
+// Load loads the store from the file at path.
+// The store is empty if the file doesn't exist.
+func Load(path string) (*Store, error) {
+	s := &Store{
+		path:  path,
+		items: map[string]*itemState{},
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state %q: %w", path, err)
+	}
+
+	f := &file{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("parsing state %q: %w", path, err)
+	}
+
+	if f.Items != nil {
+		s.items = f.Items
+	}
+
+	return s, nil
+}
+
+// Seen returns whether the item with the URL has been
+// seen since it was last updated at updatedAt.
+func (s *Store) Seen(url string, updatedAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[url]
+	return ok && !item.SeenAt.IsZero() && !item.UpdatedAt.Before(updatedAt)
+}
+
+// MarkSeen records that the item with the URL has
+// been seen since it was last updated at updatedAt.
+func (s *Store) MarkSeen(url string, updatedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.item(url)
+	item.UpdatedAt = updatedAt
+	item.SeenAt = time.Now()
+	s.dirty = true
+}
+
+// Snooze snoozes the item with the URL, replacing any existing snooze.
+func (s *Store) Snooze(url string, snooze Snooze) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snooze.At = time.Now()
+	s.item(url).Snooze = &snooze
+	s.dirty = true
+}
+
+// Restore ends any snooze of the item with the URL.
+func (s *Store) Restore(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item, ok := s.items[url]; ok && item.Snooze != nil {
+		item.Snooze = nil
+		s.dirty = true
+	}
+}
+
+// Snoozed returns the snooze of the item with the URL, which was last updated
+// at updatedAt, and whether the item is snoozed. Snoozes that have ended
+// are not returned.
+func (s *Store) Snoozed(url string, updatedAt time.Time) (Snooze, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[url]
+	if !ok || item.Snooze == nil || !item.Snooze.active(updatedAt) {
+		return Snooze{}, false
+	}
+
+	return *item.Snooze, true
+}
+
+// SnoozedUntil returns whether the item with the URL, which was last updated
+// at updatedAt, is snoozed and when the snooze ends. The time is zero if the
+// snooze ends when the item is updated or the item was dismissed.
+func (s *Store) SnoozedUntil(url string, updatedAt time.Time) (time.Time, bool) {
+	snooze, ok := s.Snoozed(url, updatedAt)
+	return snooze.Until, ok
+}
+
+// item returns the state of the item with the
+// URL, adding it if it isn't in the store yet.
+func (s *Store) item(url string) *itemState {
+	item, ok := s.items[url]
+	if !ok {
+		item = &itemState{}
+		s.items[url] = item
+	}
+	return item
+}
+
+// Save writes the store to its file if anything has changed since it
+// was loaded, forgetting items that haven't been seen in a long time.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	for url, item := range s.items {
+		if item.Snooze != nil && item.Snooze.expired() {
+			item.Snooze = nil
+		}
+
+		if item.Snooze == nil && time.Since(item.SeenAt) > retention {
+			delete(s.items, url)
+		}
+	}
+
+	data, err := json.Marshal(&file{Items: s.items})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+
+	// write to a temporary file and rename it so that
+	// the store is never left partially written
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+
+	s.dirty = false
+	return nil
+}